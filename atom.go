@@ -0,0 +1,163 @@
+package persistent
+
+import (
+	"./hashmap"
+	"sync/atomic"
+)
+
+// Function rejecting an invalid state with an error
+type Validator func(Value) error
+
+// Function notified after a reference changes from old to new
+type Watch func(key Value, old, new Value)
+
+// Concurrency-safe reference to a persistent value.
+// Updates replace the value atomically, so readers always see a complete
+// version and never block writers.
+type Atom struct {
+	// The current state, boxed so it can be swapped atomically
+	state atomic.Pointer[atomState]
+	// The validator for new states, or nil
+	validator atomic.Pointer[Validator]
+	// The map of watch keys to Watch functions
+	watches atomic.Pointer[hashmap.Map]
+}
+
+// A single version of an atom's value
+type atomState struct {
+	value Value
+}
+
+// Return a new atom holding value.
+// Complexity: O(1)
+func NewAtom(value Value) *Atom {
+	a := &Atom{}
+	a.state.Store(&atomState{value})
+	a.watches.Store(hashmap.New())
+	return a
+}
+
+// Return the current value of the atom.
+// Complexity: O(1)
+func (a *Atom) Deref() Value {
+	return a.state.Load().value
+}
+
+// Check value against the validator, if any.
+// Complexity: O(1), plus the validator
+func (a *Atom) validate(value Value) error {
+	if fn := a.validator.Load(); fn != nil {
+		if err := (*fn)(value); err != nil {
+			return &InvalidState{value, err}
+		}
+	}
+	return nil
+}
+
+// Notify every watch that the value changed from old to new.
+// Complexity: O(n) for n watches
+func (a *Atom) notify(old, new Value) {
+	a.watches.Load().Each(func(key, fn hashmap.Value) bool {
+		fn.(Watch)(key, old, new)
+		return true
+	})
+}
+
+// Replace the value with fn applied to it, retrying if another update lands
+// first, and return the new value.
+// fn may be called more than once, so must be free of side effects.
+// A new value rejected by the validator is an InvalidState error, and leaves
+// the atom unchanged.
+// Complexity: O(1) per attempt, plus fn
+func (a *Atom) Swap(fn func(Value) Value) (Value, error) {
+	for {
+		cur := a.state.Load()
+		next := &atomState{fn(cur.value)}
+
+		if err := a.validate(next.value); err != nil {
+			return nil, err
+		}
+
+		if a.state.CompareAndSwap(cur, next) {
+			a.notify(cur.value, next.value)
+			return next.value, nil
+		}
+	}
+}
+
+// Replace the value with value, regardless of the current value.
+// A value rejected by the validator is an InvalidState error.
+// Complexity: O(1)
+func (a *Atom) Reset(value Value) error {
+	if err := a.validate(value); err != nil {
+		return err
+	}
+
+	old := a.state.Swap(&atomState{value})
+	a.notify(old.value, value)
+	return nil
+}
+
+// Replace the value with new only if the current value is old (==).
+// Returns false if the value was not old.
+// A new value rejected by the validator is an InvalidState error.
+// Complexity: O(1)
+func (a *Atom) CompareAndSet(old, new Value) (bool, error) {
+	if err := a.validate(new); err != nil {
+		return false, err
+	}
+
+	for {
+		cur := a.state.Load()
+		if cur.value != old {
+			return false, nil
+		}
+
+		if a.state.CompareAndSwap(cur, &atomState{new}) {
+			a.notify(old, new)
+			return true, nil
+		}
+	}
+}
+
+// Set the validator checked before every change, or nil to remove it.
+// A current value rejected by fn is an InvalidState error, and leaves the
+// validator unchanged.
+// Complexity: O(1)
+func (a *Atom) SetValidator(fn Validator) error {
+	if fn != nil {
+		if err := fn(a.Deref()); err != nil {
+			return &InvalidState{a.Deref(), err}
+		}
+		a.validator.Store(&fn)
+	} else {
+		a.validator.Store(nil)
+	}
+	return nil
+}
+
+// Add fn under key, to be called after every change to the atom, replacing
+// any watch already under key.
+// Watches are called synchronously by the goroutine making the change.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (a *Atom) AddWatch(key Value, fn Watch) {
+	for {
+		cur := a.watches.Load()
+		if a.watches.CompareAndSwap(cur, cur.Assoc(key, fn)) {
+			return
+		}
+	}
+}
+
+// Remove the watch under key.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (a *Atom) RemoveWatch(key Value) {
+	for {
+		cur := a.watches.Load()
+		if a.watches.CompareAndSwap(cur, cur.Dissoc(key)) {
+			return
+		}
+	}
+}
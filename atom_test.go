@@ -0,0 +1,92 @@
+package persistent
+
+import (
+	"./vector"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestAtomSwap(t *testing.T) {
+	a := NewAtom(Vector())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				a.Swap(func(v Value) Value {
+					return v.(*vector.Vector).Append(i)
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if n := a.Deref().(*vector.Vector).Count(); n != 1000 {
+		t.Fatalf(`expected 1000 elements, got %d`, n)
+	}
+}
+
+func TestAtomResetAndCompareAndSet(t *testing.T) {
+	a := NewAtom(1)
+
+	if err := a.Reset(2); err != nil || a.Deref() != 2 {
+		t.Fatalf(`expected a.Reset(2) to set 2, got %v, %s`, a.Deref(), err)
+	}
+
+	if ok, _ := a.CompareAndSet(1, 3); ok || a.Deref() != 2 {
+		t.Fatalf(`expected a.CompareAndSet(1, 3) to fail`)
+	}
+	if ok, _ := a.CompareAndSet(2, 3); !ok || a.Deref() != 3 {
+		t.Fatalf(`expected a.CompareAndSet(2, 3) to succeed`)
+	}
+}
+
+func TestAtomValidator(t *testing.T) {
+	a := NewAtom(1)
+	positive := func(v Value) error {
+		if v.(int) <= 0 {
+			return errors.New("must be positive")
+		}
+		return nil
+	}
+
+	if err := a.SetValidator(positive); err != nil {
+		t.Fatalf(`expected a.SetValidator() to be ok, got %s`, err)
+	}
+
+	_, err := a.Swap(func(v Value) Value { return v.(int) - 5 })
+	if _, ok := err.(*InvalidState); !ok || a.Deref() != 1 {
+		t.Fatalf(`expected an InvalidState error leaving 1, got %v, %s`, a.Deref(), err)
+	}
+	if err := a.Reset(0); err == nil {
+		t.Fatalf(`expected a.Reset(0) not to be ok, but was`)
+	}
+	if _, err := a.CompareAndSet(1, -1); err == nil {
+		t.Fatalf(`expected a.CompareAndSet(1, -1) not to be ok, but was`)
+	}
+
+	if err := NewAtom(0).SetValidator(positive); err == nil {
+		t.Fatalf(`expected a validator rejecting the current value not to be ok, but was`)
+	}
+}
+
+func TestAtomWatch(t *testing.T) {
+	a := NewAtom(1)
+
+	var changes [][2]Value
+	a.AddWatch("log", func(key, old, new Value) {
+		changes = append(changes, [2]Value{old, new})
+	})
+
+	a.Swap(func(v Value) Value { return v.(int) + 1 })
+	a.Reset(10)
+	a.RemoveWatch("log")
+	a.Reset(11)
+
+	if len(changes) != 2 || changes[0] != [2]Value{1, 2} || changes[1] != [2]Value{2, 10} {
+		t.Fatalf(`expected changes 1->2 and 2->10, got %v`, changes)
+	}
+}
@@ -0,0 +1,142 @@
+package bag
+
+import (
+	"../hashmap"
+)
+
+// Values storable in the bag
+type Value = interface{}
+
+// Persistent multiset, counting how many times each element occurs.
+// Elements must be comparable with == and hashable by hashmap.Hash.
+type Bag struct {
+	// The map from each element to its uint32 multiplicity
+	Map *hashmap.Map
+	// The total number of occurrences in the bag
+	Length uint32
+}
+
+// Value for the empty bag
+var empty = &Bag{Map: hashmap.New()}
+
+// Return a new bag containing elements..., counting repeats.
+// Complexity: O(n)
+func New(elements ...Value) *Bag {
+	acc := empty
+	for _, v := range elements {
+		acc = acc.Add(v)
+	}
+	return acc
+}
+
+// Return the total number of occurrences in this bag.
+// Complexity: O(1)
+func (bag *Bag) Count() uint32 {
+	return bag.Length
+}
+
+// Return the number of distinct elements in this bag.
+// Complexity: O(1)
+func (bag *Bag) Distinct() uint32 {
+	return bag.Map.Count()
+}
+
+// Return the number of times value occurs in the bag.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (bag *Bag) Multiplicity(value Value) uint32 {
+	if n, ok := bag.Map.Get(value); ok {
+		return n.(uint32)
+	}
+	return 0
+}
+
+// Add one occurrence of value.
+// A new bag is returned, sharing memory with the original.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (bag *Bag) Add(value Value) *Bag {
+	return bag.AddN(value, 1)
+}
+
+// Add n occurrences of value.
+// A new bag is returned, sharing memory with the original.
+// Adding zero occurrences returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (bag *Bag) AddN(value Value, n uint32) *Bag {
+	if n == 0 {
+		return bag
+	}
+	return &Bag{
+		Map:    bag.Map.Assoc(value, bag.Multiplicity(value)+n),
+		Length: bag.Length + n,
+	}
+}
+
+// Remove one occurrence of value.
+// A new bag is returned, sharing memory with the original.
+// Removing a value not in the bag returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (bag *Bag) Remove(value Value) *Bag {
+	switch n := bag.Multiplicity(value); n {
+	case 0:
+		return bag
+	case 1:
+		return &Bag{Map: bag.Map.Dissoc(value), Length: bag.Length - 1}
+	default:
+		return &Bag{Map: bag.Map.Assoc(value, n-1), Length: bag.Length - 1}
+	}
+}
+
+// Remove every occurrence of value.
+// A new bag is returned, sharing memory with the original.
+// Removing a value not in the bag returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (bag *Bag) RemoveAll(value Value) *Bag {
+	n := bag.Multiplicity(value)
+	if n == 0 {
+		return bag
+	}
+	return &Bag{Map: bag.Map.Dissoc(value), Length: bag.Length - n}
+}
+
+// Return a bag whose multiplicities are the sum of this bag's and other's.
+// A new bag is returned, sharing memory with both originals.
+// Complexity: O(n + m)
+func (bag *Bag) Sum(other *Bag) *Bag {
+	m := bag.Map.Merge(other.Map, func(_, a, b hashmap.Value) hashmap.Value {
+		return a.(uint32) + b.(uint32)
+	})
+	return &Bag{Map: m, Length: bag.Length + other.Length}
+}
+
+// Return a bag whose multiplicities are the greater of this bag's and
+// other's.
+// A new bag is returned, sharing memory with both originals.
+// Complexity: O(n + m)
+func (bag *Bag) Union(other *Bag) *Bag {
+	var overlap uint32
+
+	m := bag.Map.Merge(other.Map, func(_, a, b hashmap.Value) hashmap.Value {
+		x, y := a.(uint32), b.(uint32)
+		if x < y {
+			x, y = y, x
+		}
+		overlap += y
+		return x
+	})
+
+	return &Bag{Map: m, Length: bag.Length + other.Length - overlap}
+}
+
+// Call fn with each distinct element and its multiplicity, until fn returns
+// false.
+// Complexity: O(n)
+func (bag *Bag) Each(fn func(value Value, n uint32) bool) {
+	bag.Map.Each(func(k, n hashmap.Value) bool {
+		return fn(k, n.(uint32))
+	})
+}
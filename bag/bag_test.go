@@ -0,0 +1,62 @@
+package bag
+
+import (
+	"testing"
+)
+
+func AssertMultiplicities(t *testing.T, bag *Bag, counts map[Value]uint32) {
+	var total uint32
+	for v, n := range counts {
+		if bag.Multiplicity(v) != n {
+			t.Fatalf(`expected bag.Multiplicity(%v) == %d, got %d`, v, n, bag.Multiplicity(v))
+		}
+		total += n
+	}
+
+	if bag.Count() != total {
+		t.Fatalf(`expected bag.Count() == %d, got %d`, total, bag.Count())
+	}
+	if bag.Distinct() != uint32(len(counts)) {
+		t.Fatalf(`expected bag.Distinct() == %d, got %d`, len(counts), bag.Distinct())
+	}
+}
+
+func TestAdd(t *testing.T) {
+	bag := New("a", "b", "a")
+	cpy := bag.Add("c").AddN("a", 3)
+
+	AssertMultiplicities(t, bag, map[Value]uint32{"a": 2, "b": 1})
+	AssertMultiplicities(t, cpy, map[Value]uint32{"a": 5, "b": 1, "c": 1})
+
+	if bag.AddN("z", 0) != bag {
+		t.Fatalf(`expected adding zero occurrences to return itself`)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	bag := New("a", "b", "a")
+
+	AssertMultiplicities(t, bag.Remove("a"), map[Value]uint32{"a": 1, "b": 1})
+	AssertMultiplicities(t, bag.Remove("b"), map[Value]uint32{"a": 2})
+	AssertMultiplicities(t, bag.RemoveAll("a"), map[Value]uint32{"b": 1})
+
+	if bag.Remove("z") != bag || bag.RemoveAll("z") != bag {
+		t.Fatalf(`expected removing a missing value to return itself`)
+	}
+}
+
+func TestSum(t *testing.T) {
+	a := New("x", "x", "y")
+	b := New("x", "z")
+
+	AssertMultiplicities(t, a.Sum(b), map[Value]uint32{"x": 3, "y": 1, "z": 1})
+	AssertMultiplicities(t, a.Sum(a), map[Value]uint32{"x": 4, "y": 2})
+}
+
+func TestUnion(t *testing.T) {
+	a := New("x", "x", "y")
+	b := New("x", "z", "z", "y", "y", "y")
+
+	AssertMultiplicities(t, a.Union(b), map[Value]uint32{"x": 2, "y": 3, "z": 2})
+	AssertMultiplicities(t, a, map[Value]uint32{"x": 2, "y": 1})
+}
@@ -0,0 +1,132 @@
+package bimap
+
+import (
+	"../hashmap"
+)
+
+// Values storable in the map
+type Value = interface{}
+
+// A key and its value
+type Pair struct {
+	// The key of the pair
+	Key Value
+	// The value of the pair
+	Value Value
+}
+
+// Persistent bidirectional map enforcing a one-to-one relation.
+// Each key maps to exactly one value, and each value to exactly one key.
+// Keys and values must be comparable with == and hashable by hashmap.Hash.
+type BiMap struct {
+	// The map from keys to values
+	Forward *hashmap.Map
+	// The map from values to keys
+	Backward *hashmap.Map
+}
+
+// Value for the empty map
+var empty = &BiMap{
+	Forward:  hashmap.New(),
+	Backward: hashmap.New(),
+}
+
+// Return a new empty map.
+// Complexity: O(1)
+func New() *BiMap {
+	return empty
+}
+
+// Return the number of pairs in this map.
+// Complexity: O(1)
+func (m *BiMap) Count() uint32 {
+	return m.Forward.Count()
+}
+
+// Get the value for key.
+// Returns false if key is not in the map.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (m *BiMap) GetByKey(key Value) (Value, bool) {
+	return m.Forward.Get(key)
+}
+
+// Get the key for value.
+// Returns false if value is not in the map.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (m *BiMap) GetByValue(value Value) (Value, bool) {
+	return m.Backward.Get(value)
+}
+
+// Associate key with value.
+// Any existing pair using key or value is removed to keep the relation
+// one-to-one, and reported as displaced.
+// A new map is returned, sharing memory with the original.
+// Putting a pair that is already present returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (m *BiMap) Put(key, value Value) (*BiMap, []Pair) {
+	var displaced []Pair
+
+	oldValue, hasKey := m.Forward.Get(key)
+	oldKey, hasValue := m.Backward.Get(value)
+	if hasKey && hasValue && oldKey == key {
+		return m, nil
+	}
+
+	forward, backward := m.Forward, m.Backward
+	if hasKey {
+		displaced = append(displaced, Pair{key, oldValue})
+		backward = backward.Dissoc(oldValue)
+	}
+	if hasValue {
+		displaced = append(displaced, Pair{oldKey, value})
+		forward = forward.Dissoc(oldKey)
+	}
+
+	return &BiMap{
+		Forward:  forward.Assoc(key, value),
+		Backward: backward.Assoc(value, key),
+	}, displaced
+}
+
+// Remove key and its value.
+// A new map is returned, sharing memory with the original.
+// Removing a key that is not in the map returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (m *BiMap) RemoveKey(key Value) *BiMap {
+	value, ok := m.Forward.Get(key)
+	if !ok {
+		return m
+	}
+	return &BiMap{
+		Forward:  m.Forward.Dissoc(key),
+		Backward: m.Backward.Dissoc(value),
+	}
+}
+
+// Remove value and its key.
+// A new map is returned, sharing memory with the original.
+// Removing a value that is not in the map returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (m *BiMap) RemoveValue(value Value) *BiMap {
+	return m.Inverse().RemoveKey(value).Inverse()
+}
+
+// Return the map with keys and values swapped.
+// The inverse shares all memory with the original.
+// Complexity: O(1)
+func (m *BiMap) Inverse() *BiMap {
+	return &BiMap{Forward: m.Backward, Backward: m.Forward}
+}
+
+// Call fn with each key and value, until fn returns false.
+// Complexity: O(n)
+func (m *BiMap) Each(fn func(key, value Value) bool) {
+	m.Forward.Each(func(k, v hashmap.Value) bool {
+		return fn(k, v)
+	})
+}
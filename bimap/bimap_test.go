@@ -0,0 +1,84 @@
+package bimap
+
+import (
+	"testing"
+)
+
+func AssertPairs(t *testing.T, m *BiMap, pairs map[Value]Value) {
+	for k, v := range pairs {
+		if x, ok := m.GetByKey(k); !ok || x != v {
+			t.Fatalf(`expected m.GetByKey(%v) == %v, got %v`, k, v, x)
+		}
+		if x, ok := m.GetByValue(v); !ok || x != k {
+			t.Fatalf(`expected m.GetByValue(%v) == %v, got %v`, v, k, x)
+		}
+	}
+	if m.Count() != uint32(len(pairs)) || m.Backward.Count() != uint32(len(pairs)) {
+		t.Fatalf(`expected m.Count() == %d, got %d`, len(pairs), m.Count())
+	}
+}
+
+func Put(t *testing.T, m *BiMap, k, v Value, displaced []Pair) *BiMap {
+	m, found := m.Put(k, v)
+	if len(found) != len(displaced) {
+		t.Fatalf(`expected m.Put(%v, %v) to displace %v, got %v`, k, v, displaced, found)
+	}
+	for i, p := range displaced {
+		if found[i] != p {
+			t.Fatalf(`expected m.Put(%v, %v) to displace %v, got %v`, k, v, displaced, found)
+		}
+	}
+	return m
+}
+
+func TestPut(t *testing.T) {
+	m := New()
+	m = Put(t, m, 1, "one", nil)
+	m = Put(t, m, 2, "two", nil)
+
+	AssertPairs(t, m, map[Value]Value{1: "one", 2: "two"})
+
+	if cpy, displaced := m.Put(1, "one"); cpy != m || displaced != nil {
+		t.Fatalf(`expected putting an existing pair to return itself`)
+	}
+}
+
+func TestPutDisplaces(t *testing.T) {
+	m := Put(t, New(), 1, "one", nil)
+	m = Put(t, m, 2, "two", nil)
+
+	byKey := Put(t, m, 1, "uno", []Pair{{1, "one"}})
+	AssertPairs(t, byKey, map[Value]Value{1: "uno", 2: "two"})
+
+	byValue := Put(t, m, 3, "two", []Pair{{2, "two"}})
+	AssertPairs(t, byValue, map[Value]Value{1: "one", 3: "two"})
+
+	both := Put(t, m, 1, "two", []Pair{{1, "one"}, {2, "two"}})
+	AssertPairs(t, both, map[Value]Value{1: "two"})
+
+	AssertPairs(t, m, map[Value]Value{1: "one", 2: "two"})
+}
+
+func TestRemove(t *testing.T) {
+	m := Put(t, New(), 1, "one", nil)
+	m = Put(t, m, 2, "two", nil)
+
+	AssertPairs(t, m.RemoveKey(1), map[Value]Value{2: "two"})
+	AssertPairs(t, m.RemoveValue("two"), map[Value]Value{1: "one"})
+
+	if m.RemoveKey(3) != m {
+		t.Fatalf(`expected removing a missing key to return itself`)
+	}
+}
+
+func TestInverse(t *testing.T) {
+	m := Put(t, New(), 1, "one", nil)
+	m = Put(t, m, 2, "two", nil)
+
+	inv := m.Inverse()
+	AssertPairs(t, inv, map[Value]Value{"one": 1, "two": 2})
+
+	if inv.Forward != m.Backward || inv.Inverse().Forward != m.Forward {
+		t.Fatalf(`expected the inverse to share both indices`)
+	}
+}
@@ -0,0 +1,155 @@
+package bitset
+
+import (
+	"../vector"
+)
+
+// Persistent set of uint32 integers.
+// This uses the same bit-partitioned trie as vector.Vector, with 64-bit words
+// at the leaves instead of values, so each leaf covers 2048 integers.
+type Bitset struct {
+	// The root node of the trie, nil when empty
+	Root *Node
+	// The number of bits to shift off at the root
+	Shift uint32
+}
+
+// Value for the empty bitset
+var empty = &Bitset{}
+
+// Return a new bitset with the bits in elements... set.
+// Complexity: O(n)
+func New(elements ...uint32) *Bitset {
+	acc := empty
+	for _, i := range elements {
+		acc = acc.Set(i)
+	}
+	return acc
+}
+
+// Return the number of bits set.
+// Complexity: O(1)
+func (set *Bitset) Count() uint32 {
+	return set.Root.count()
+}
+
+// Return the shift needed for a root covering word index w.
+// Complexity: O(1)
+func shiftFor(w uint32) uint32 {
+	var shift uint32
+	for shift < 32 && w>>(shift+vector.BITS) > 0 {
+		shift += vector.BITS
+	}
+	return shift
+}
+
+// Return the root of this bitset extended to a higher shift.
+// The existing root becomes the leftmost child of each new level.
+// Complexity: O(log(n))
+func (set *Bitset) grow(shift uint32) *Node {
+	root := set.Root
+	for s := set.Shift; s < shift; s += vector.BITS {
+		if root != nil {
+			children := make([]*Node, vector.SIZE)
+			children[0] = root
+			root = &Node{Children: children, Count: root.Count}
+		}
+	}
+	return root
+}
+
+// Return true if bit i is set.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (set *Bitset) Test(i uint32) bool {
+	w := i >> WORD_BITS
+	if shiftFor(w) > set.Shift {
+		return false
+	}
+	return set.Root.test(set.Shift, w, i&WORD_MASK)
+}
+
+// Set bit i.
+// A new bitset is returned, sharing memory with the original.
+// Setting a bit that is already set returns itself.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (set *Bitset) Set(i uint32) *Bitset {
+	w := i >> WORD_BITS
+
+	shift := set.Shift
+	if s := shiftFor(w); s > shift {
+		shift = s
+	}
+
+	root := set.grow(shift).update(shift, w, i&WORD_MASK, true)
+	if root == set.Root {
+		return set
+	}
+	return &Bitset{Root: root, Shift: shift}
+}
+
+// Clear bit i.
+// A new bitset is returned, sharing memory with the original.
+// Clearing a bit that is not set returns itself.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (set *Bitset) Clear(i uint32) *Bitset {
+	w := i >> WORD_BITS
+	if shiftFor(w) > set.Shift {
+		return set
+	}
+
+	root := set.Root.update(set.Shift, w, i&WORD_MASK, false)
+	if root == set.Root {
+		return set
+	}
+	return &Bitset{Root: root, Shift: set.Shift}
+}
+
+// Combine this bitset with other using op.
+// Complexity: O(n)
+func (set *Bitset) merge(op operation, other *Bitset) *Bitset {
+	shift := set.Shift
+	if other.Shift > shift {
+		shift = other.Shift
+	}
+
+	a, b := set.grow(shift), other.grow(shift)
+	root := merge(op, a, b, shift)
+
+	switch {
+	case root == set.Root && shift == set.Shift:
+		return set
+	case root == other.Root && shift == other.Shift:
+		return other
+	}
+	return &Bitset{Root: root, Shift: shift}
+}
+
+// Return the bits set in either this bitset or other.
+// A new bitset is returned, sharing untouched subtrees with both originals.
+// Complexity: O(n)
+func (set *Bitset) Union(other *Bitset) *Bitset {
+	return set.merge(union, other)
+}
+
+// Return the bits set in both this bitset and other.
+// A new bitset is returned, sharing untouched subtrees with both originals.
+// Complexity: O(n)
+func (set *Bitset) Intersect(other *Bitset) *Bitset {
+	return set.merge(intersect, other)
+}
+
+// Return the bits set in exactly one of this bitset and other.
+// A new bitset is returned, sharing untouched subtrees with both originals.
+// Complexity: O(n)
+func (set *Bitset) Xor(other *Bitset) *Bitset {
+	return set.merge(xor, other)
+}
+
+// Call fn with each set bit in ascending order, until fn returns false.
+// Complexity: O(n)
+func (set *Bitset) Each(fn func(uint32) bool) {
+	set.Root.each(set.Shift, 0, fn)
+}
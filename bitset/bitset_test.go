@@ -0,0 +1,102 @@
+package bitset
+
+import (
+	"testing"
+)
+
+func AssertBits(t *testing.T, set *Bitset, elems []uint32) {
+	var found []uint32
+	set.Each(func(i uint32) bool {
+		found = append(found, i)
+		return true
+	})
+
+	if len(found) != len(elems) {
+		t.Fatalf(`expected bits %v, got %v`, elems, found)
+	}
+	for i, v := range elems {
+		if found[i] != v {
+			t.Fatalf(`expected bits %v, got %v`, elems, found)
+		}
+		if !set.Test(v) {
+			t.Fatalf(`expected set.Test(%d), but was not set`, v)
+		}
+	}
+
+	if set.Count() != uint32(len(elems)) {
+		t.Fatalf(`expected set.Count() == %d, got %d`, len(elems), set.Count())
+	}
+}
+
+func TestSetAndTest(t *testing.T) {
+	set := New(3, 64, 70, 2047, 2048, 100000, 4294967295)
+
+	AssertBits(t, set, []uint32{3, 64, 70, 2047, 2048, 100000, 4294967295})
+
+	for _, i := range []uint32{0, 4, 63, 65, 2049, 99999, 4294967294} {
+		if set.Test(i) {
+			t.Fatalf(`expected set.Test(%d) to be false`, i)
+		}
+	}
+
+	if set.Set(64) != set {
+		t.Fatalf(`expected setting a set bit to return itself`)
+	}
+}
+
+func TestClear(t *testing.T) {
+	set := New(1, 2, 3, 5000)
+	cpy := set.Clear(2).Clear(5000)
+
+	AssertBits(t, cpy, []uint32{1, 3})
+	AssertBits(t, set, []uint32{1, 2, 3, 5000})
+
+	if cpy.Clear(2) != cpy || cpy.Clear(1<<30) != cpy {
+		t.Fatalf(`expected clearing an unset bit to return itself`)
+	}
+
+	cpy = cpy.Clear(1).Clear(3)
+	if cpy.Root != nil {
+		t.Fatalf(`expected an empty bitset to have no nodes`)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	a := New(1, 2, 3)
+	b := New(3, 4, 70000)
+
+	AssertBits(t, a.Union(b), []uint32{1, 2, 3, 4, 70000})
+	AssertBits(t, a, []uint32{1, 2, 3})
+
+	if a.Union(New()) != a {
+		t.Fatalf(`expected a union with the empty set to return itself`)
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	a := New(1, 2, 3, 70000, 80000)
+	b := New(3, 4, 70000)
+
+	AssertBits(t, a.Intersect(b), []uint32{3, 70000})
+	AssertBits(t, a.Intersect(New()), nil)
+}
+
+func TestXor(t *testing.T) {
+	a := New(1, 2, 3, 70000)
+	b := New(3, 4, 70000)
+
+	AssertBits(t, a.Xor(b), []uint32{1, 2, 4})
+	AssertBits(t, a.Xor(a), nil)
+}
+
+func TestMergeSharesUntouchedSubtrees(t *testing.T) {
+	a := New(1, 100000)
+	b := New(2)
+
+	u := a.Union(b)
+	far := uint32(100000 >> WORD_BITS)
+
+	if u.Root.Children[(far>>u.Shift)&31] != a.Root.Children[(far>>a.Shift)&31] {
+		t.Fatalf(`expected the subtree only in a to be shared`)
+	}
+}
@@ -0,0 +1,199 @@
+package bitset
+
+import (
+	"../vector"
+	"math/bits"
+)
+
+const (
+	// The number of bits to read for the bit index within a word
+	WORD_BITS = 6
+	// The bits we're interested in for the bit index within a word
+	WORD_MASK = 1<<WORD_BITS - 1
+)
+
+// Representation of a bitset trie node.
+// Leaves hold vector.SIZE words of 64 bits, branches hold vector.SIZE
+// children. A nil child stands for a subtree with no bits set.
+type Node struct {
+	// The children of a branch
+	Children []*Node
+	// The words of a leaf
+	Words []uint64
+	// The number of bits set in this subtree
+	Count uint32
+}
+
+// The set operations which combine two tries
+type operation int
+
+const (
+	union operation = iota
+	intersect
+	xor
+)
+
+// Apply op to a pair of words.
+// Complexity: O(1)
+func (op operation) apply(a, b uint64) uint64 {
+	switch op {
+	case union:
+		return a | b
+	case intersect:
+		return a & b
+	}
+	return a ^ b
+}
+
+// Return the number of bits set in node, where nil has none.
+// Complexity: O(1)
+func (node *Node) count() uint32 {
+	if node == nil {
+		return 0
+	}
+	return node.Count
+}
+
+// Return true if the bit at word w, bit b is set beneath node.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (node *Node) test(shift, w, b uint32) bool {
+	for node != nil && shift > 0 {
+		node = node.Children[(w>>shift)&vector.MASK]
+		shift -= vector.BITS
+	}
+	return node != nil && node.Words[w&vector.MASK]&(1<<b) != 0
+}
+
+// Set or clear the bit at word w, bit b, returning a new node.
+// Returns node itself if the bit already has that state.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (node *Node) update(shift, w, b uint32, set bool) *Node {
+	if node == nil {
+		if !set {
+			return nil
+		}
+		if shift == 0 {
+			node = &Node{Words: make([]uint64, vector.SIZE)}
+		} else {
+			node = &Node{Children: make([]*Node, vector.SIZE)}
+		}
+	}
+
+	var into *Node
+	if shift == 0 {
+		idx := w & vector.MASK
+		word := node.Words[idx] &^ (1 << b)
+		if set {
+			word |= 1 << b
+		}
+		if word == node.Words[idx] {
+			return node
+		}
+		into = &Node{Words: append([]uint64(nil), node.Words...)}
+		into.Words[idx] = word
+	} else {
+		idx := (w >> shift) & vector.MASK
+		child := node.Children[idx].update(shift-vector.BITS, w, b, set)
+		if child == node.Children[idx] {
+			return node
+		}
+		into = &Node{Children: append([]*Node(nil), node.Children...)}
+		into.Children[idx] = child
+	}
+
+	into.Count = node.Count - 1
+	if set {
+		into.Count = node.Count + 1
+	}
+	if into.Count == 0 {
+		return nil
+	}
+	return into
+}
+
+// Combine two nodes at the same depth with op, returning a new node.
+// Subtrees unaffected by the operation are shared with the inputs.
+// Complexity: O(n)
+func merge(op operation, a, b *Node, shift uint32) *Node {
+	if a == b {
+		if op == xor {
+			return nil
+		}
+		return a
+	}
+	if a == nil || b == nil {
+		switch {
+		case op == intersect:
+			return nil
+		case a == nil:
+			return b
+		}
+		return a
+	}
+
+	var (
+		into         = &Node{}
+		sameA, sameB = true, true
+	)
+
+	if shift == 0 {
+		into.Words = make([]uint64, vector.SIZE)
+		for i := range into.Words {
+			word := op.apply(a.Words[i], b.Words[i])
+			into.Words[i] = word
+			into.Count += uint32(bits.OnesCount64(word))
+			sameA = sameA && word == a.Words[i]
+			sameB = sameB && word == b.Words[i]
+		}
+	} else {
+		into.Children = make([]*Node, vector.SIZE)
+		for i := range into.Children {
+			child := merge(op, a.Children[i], b.Children[i], shift-vector.BITS)
+			into.Children[i] = child
+			into.Count += child.count()
+			sameA = sameA && child == a.Children[i]
+			sameB = sameB && child == b.Children[i]
+		}
+	}
+
+	switch {
+	case sameA:
+		return a
+	case sameB:
+		return b
+	case into.Count == 0:
+		return nil
+	}
+	return into
+}
+
+// Visit each set bit beneath node in order, where base is the first word
+// index covered by node, until fn returns false.
+// Complexity: O(n)
+func (node *Node) each(shift, base uint32, fn func(uint32) bool) bool {
+	if node == nil {
+		return true
+	}
+
+	if shift == 0 {
+		for i, word := range node.Words {
+			for word != 0 {
+				b := uint32(bits.TrailingZeros64(word))
+				if !fn((base+uint32(i))<<WORD_BITS | b) {
+					return false
+				}
+				word &= word - 1
+			}
+		}
+		return true
+	}
+
+	for i, child := range node.Children {
+		if !child.each(shift-vector.BITS, base+uint32(i)<<shift, fn) {
+			return false
+		}
+	}
+	return true
+}
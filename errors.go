@@ -0,0 +1,79 @@
+package persistent
+
+import (
+	"fmt"
+)
+
+// Error type returned when a nested path names a missing key or index
+type KeyNotFound struct {
+	// The path up to and including the missing key
+	Path []Value
+}
+
+func (e *KeyNotFound) Error() string {
+	return fmt.Sprintf("key %v not found at path %v", e.Path[len(e.Path)-1], e.Path)
+}
+
+// Error type returned when a key cannot index its collection
+type InvalidKey struct {
+	// The path up to and including the invalid key
+	Path []Value
+}
+
+func (e *InvalidKey) Error() string {
+	return fmt.Sprintf("key %v is not a valid index at path %v", e.Path[len(e.Path)-1], e.Path)
+}
+
+// Error type returned when a path descends into a value that is not a
+// vector or map
+type NotAssociative struct {
+	// The path up to and including the key that cannot be looked up
+	Path []Value
+}
+
+func (e *NotAssociative) Error() string {
+	return fmt.Sprintf("cannot look up key %v in a non-collection at path %v", e.Path[len(e.Path)-1], e.Path)
+}
+
+// Error type returned when updating with an empty path
+type EmptyPath struct{}
+
+func (e *EmptyPath) Error() string {
+	return "path is empty"
+}
+
+// Error type returned when a validator rejects a new state
+type InvalidState struct {
+	// The rejected state
+	Value Value
+	// The error returned by the validator
+	Err error
+}
+
+func (e *InvalidState) Error() string {
+	return fmt.Sprintf("invalid state %v: %s", e.Value, e.Err)
+}
+
+func (e *InvalidState) Unwrap() error {
+	return e.Err
+}
+
+// Error type returned when a transaction conflicts more than its retry limit
+type RetryLimit struct {
+	// The number of attempts made
+	Attempts int
+}
+
+func (e *RetryLimit) Error() string {
+	return fmt.Sprintf("transaction gave up after %d attempts", e.Attempts)
+}
+
+// Error type returned when a transaction is still retrying after its timeout
+type TxTimeout struct {
+	// The number of attempts made
+	Attempts int
+}
+
+func (e *TxTimeout) Error() string {
+	return fmt.Sprintf("transaction timed out after %d attempts", e.Attempts)
+}
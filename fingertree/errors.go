@@ -0,0 +1,8 @@
+package fingertree
+
+// Error type returned when reading from an empty tree
+type EmptyTree struct{}
+
+func (e *EmptyTree) Error() string {
+	return "finger tree is empty"
+}
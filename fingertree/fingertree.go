@@ -0,0 +1,136 @@
+package fingertree
+
+// Values storable in the tree
+type Value = interface{}
+
+// Persistent deque annotated with a monoidal measure.
+// This implements the 2-3 finger tree described by Hinze and Paterson.
+type FingerTree struct {
+	// The measure used to annotate subtrees
+	Measurer Measurer
+	// The root of the tree
+	root tree
+}
+
+// Return a new tree using m, containing elements...
+// Complexity: O(n)
+func New(m Measurer, elements ...Value) *FingerTree {
+	return &FingerTree{
+		Measurer: m,
+		root:     digitToTree(m, elements),
+	}
+}
+
+// Return a tree sharing this tree's measurer, wrapping root.
+// Complexity: O(1)
+func (t *FingerTree) with(root tree) *FingerTree {
+	return &FingerTree{
+		Measurer: t.Measurer,
+		root:     root,
+	}
+}
+
+// Return true if the tree contains no elements.
+// Complexity: O(1)
+func (t *FingerTree) IsEmpty() bool {
+	return t.root == empty
+}
+
+// Return the combined measure of every element in the tree.
+// Complexity: O(1)
+func (t *FingerTree) Measure() Value {
+	return t.root.measure(t.Measurer)
+}
+
+// Get the first element of the tree.
+// Reading from an empty tree is an EmptyTree error.
+// Complexity: O(1)
+func (t *FingerTree) Front() (Value, error) {
+	switch root := t.root.(type) {
+	case *singleTree:
+		return root.Element, nil
+	case *deepTree:
+		return root.Prefix[0], nil
+	}
+	return nil, &EmptyTree{}
+}
+
+// Get the last element of the tree.
+// Reading from an empty tree is an EmptyTree error.
+// Complexity: O(1)
+func (t *FingerTree) Back() (Value, error) {
+	switch root := t.root.(type) {
+	case *singleTree:
+		return root.Element, nil
+	case *deepTree:
+		return root.Suffix[len(root.Suffix)-1], nil
+	}
+	return nil, &EmptyTree{}
+}
+
+// Push a value onto the front of the tree.
+// A new tree is returned, sharing memory with the original.
+// Complexity: O(1) amortized
+func (t *FingerTree) PushFront(value Value) *FingerTree {
+	return t.with(pushFront(t.Measurer, t.root, value))
+}
+
+// Push a value onto the back of the tree.
+// A new tree is returned, sharing memory with the original.
+// Complexity: O(1) amortized
+func (t *FingerTree) PushBack(value Value) *FingerTree {
+	return t.with(pushBack(t.Measurer, t.root, value))
+}
+
+// Return the tree with the first element removed.
+// A new tree is returned, sharing memory with the original.
+// Attempting to pop an empty tree returns itself.
+// Complexity: O(1) amortized
+func (t *FingerTree) PopFront() *FingerTree {
+	_, rest, ok := viewFront(t.Measurer, t.root)
+	if !ok {
+		return t
+	}
+	return t.with(rest)
+}
+
+// Return the tree with the last element removed.
+// A new tree is returned, sharing memory with the original.
+// Attempting to pop an empty tree returns itself.
+// Complexity: O(1) amortized
+func (t *FingerTree) PopBack() *FingerTree {
+	_, rest, ok := viewBack(t.Measurer, t.root)
+	if !ok {
+		return t
+	}
+	return t.with(rest)
+}
+
+// Return the elements of this tree followed by the elements of other.
+// Both trees are expected to use the same measurer.
+// A new tree is returned, sharing memory with both originals.
+// Complexity: O(log(min(n, m)))
+func (t *FingerTree) Concat(other *FingerTree) *FingerTree {
+	return t.with(app3(t.Measurer, t.root, nil, other.root))
+}
+
+// Split the tree before the first element at which pred holds for the
+// measure of all elements up to and including it.
+// The predicate must be monotonic: once true, it must stay true.
+// If pred never holds, the right tree is empty.
+// Both trees share memory with the original.
+// Complexity: O(log(n))
+func (t *FingerTree) SplitAt(pred func(Value) bool) (*FingerTree, *FingerTree) {
+	if t.IsEmpty() || !pred(t.Measure()) {
+		return t, t.with(empty)
+	}
+
+	l, x, r := splitTree(t.Measurer, pred, t.Measurer.Identity(), t.root)
+	return t.with(l), t.with(pushFront(t.Measurer, r, x))
+}
+
+// Call fn with each element in order, until fn returns false.
+// Complexity: O(n)
+func (t *FingerTree) Each(fn func(Value) bool) {
+	each(t.root, fn)
+}
@@ -0,0 +1,185 @@
+package fingertree
+
+import (
+	"testing"
+)
+
+// Measurer for the largest int in the tree
+type maxMeasurer struct{}
+
+func (maxMeasurer) Identity() Value {
+	return -1 << 31
+}
+
+func (maxMeasurer) Measure(x Value) Value {
+	return x
+}
+
+func (maxMeasurer) Combine(a, b Value) Value {
+	if a.(int) > b.(int) {
+		return a
+	}
+	return b
+}
+
+func AssertElements(t *testing.T, tree *FingerTree, elems []Value) {
+	var found []Value
+	tree.Each(func(x Value) bool {
+		found = append(found, x)
+		return true
+	})
+
+	if len(found) != len(elems) {
+		t.Fatalf(`expected %d elements, got %d (%v)`, len(elems), len(found), found)
+	}
+	for i, v := range elems {
+		if found[i] != v {
+			t.Fatalf(`expected element %d == %v, got %v`, i, v, found[i])
+		}
+	}
+}
+
+func Range(from, to int) []Value {
+	elems := make([]Value, 0, to-from)
+	for i := from; i < to; i++ {
+		elems = append(elems, i)
+	}
+	return elems
+}
+
+func TestEmpty(t *testing.T) {
+	tree := New(Size)
+
+	if !tree.IsEmpty() {
+		t.Fatalf(`expected tree.IsEmpty()`)
+	}
+	if tree.Measure() != uint32(0) {
+		t.Fatalf(`expected tree.Measure() == 0, got %v`, tree.Measure())
+	}
+	if _, err := tree.Front(); err == nil {
+		t.Fatalf(`expected tree.Front() not to be ok, but was`)
+	}
+	if _, err := tree.Back(); err == nil {
+		t.Fatalf(`expected tree.Back() not to be ok, but was`)
+	}
+	if tree.PopFront() != tree || tree.PopBack() != tree {
+		t.Fatalf(`expected popping an empty tree to return itself`)
+	}
+}
+
+func TestPushBack(t *testing.T) {
+	tree := New(Size)
+	for i := 0; i < 1000; i++ {
+		tree = tree.PushBack(i)
+	}
+
+	AssertElements(t, tree, Range(0, 1000))
+
+	if tree.Measure() != uint32(1000) {
+		t.Fatalf(`expected tree.Measure() == 1000, got %v`, tree.Measure())
+	}
+}
+
+func TestPushFront(t *testing.T) {
+	tree := New(Size)
+	for i := 999; i >= 0; i-- {
+		tree = tree.PushFront(i)
+	}
+
+	AssertElements(t, tree, Range(0, 1000))
+}
+
+func TestPopFrontAndBack(t *testing.T) {
+	tree := New(Size, Range(0, 100)...)
+	cpy := tree
+
+	for i := 0; i < 50; i++ {
+		front, err := cpy.Front()
+		if err != nil {
+			t.Fatalf(`expected cpy.Front() to be ok, got %s`, err)
+		}
+		if front != i {
+			t.Fatalf(`expected cpy.Front() == %d, got %v`, i, front)
+		}
+
+		back, err := cpy.Back()
+		if err != nil {
+			t.Fatalf(`expected cpy.Back() to be ok, got %s`, err)
+		}
+		if back != 99-i {
+			t.Fatalf(`expected cpy.Back() == %d, got %v`, 99-i, back)
+		}
+
+		cpy = cpy.PopFront().PopBack()
+	}
+
+	if !cpy.IsEmpty() {
+		t.Fatalf(`expected cpy.IsEmpty()`)
+	}
+
+	AssertElements(t, tree, Range(0, 100))
+}
+
+func TestConcat(t *testing.T) {
+	for _, n := range []int{0, 1, 5, 37, 200} {
+		left := New(Size, Range(0, n)...)
+		right := New(Size, Range(n, 2*n+3)...)
+
+		both := left.Concat(right)
+
+		AssertElements(t, both, Range(0, 2*n+3))
+		AssertElements(t, left, Range(0, n))
+		AssertElements(t, right, Range(n, 2*n+3))
+
+		if both.Measure() != uint32(2*n+3) {
+			t.Fatalf(`expected both.Measure() == %d, got %v`, 2*n+3, both.Measure())
+		}
+	}
+}
+
+func TestSplitAtPosition(t *testing.T) {
+	tree := New(Size, Range(0, 300)...)
+
+	for _, i := range []uint32{0, 1, 17, 150, 299} {
+		left, right := tree.SplitAt(func(v Value) bool {
+			return v.(uint32) > i
+		})
+
+		AssertElements(t, left, Range(0, int(i)))
+		AssertElements(t, right, Range(int(i), 300))
+	}
+
+	AssertElements(t, tree, Range(0, 300))
+}
+
+func TestSplitAtNeverHolds(t *testing.T) {
+	tree := New(Size, Range(0, 10)...)
+
+	left, right := tree.SplitAt(func(v Value) bool {
+		return v.(uint32) > 10
+	})
+
+	AssertElements(t, left, Range(0, 10))
+	if !right.IsEmpty() {
+		t.Fatalf(`expected right.IsEmpty()`)
+	}
+}
+
+func TestCustomMeasure(t *testing.T) {
+	tree := New(maxMeasurer{}, 3, 9, 4, 12, 7, 1)
+
+	if tree.Measure() != 12 {
+		t.Fatalf(`expected tree.Measure() == 12, got %v`, tree.Measure())
+	}
+
+	left, right := tree.SplitAt(func(v Value) bool {
+		return v.(int) >= 9
+	})
+
+	AssertElements(t, left, []Value{3})
+	AssertElements(t, right, []Value{9, 4, 12, 7, 1})
+
+	if popped := tree.PopBack().PopBack().PopBack(); popped.Measure() != 9 {
+		t.Fatalf(`expected popped.Measure() == 9, got %v`, popped.Measure())
+	}
+}
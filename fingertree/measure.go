@@ -0,0 +1,49 @@
+package fingertree
+
+// A monoid used to annotate each subtree with a cached measurement.
+// Combine must be associative and Identity must be its identity element.
+type Measurer interface {
+	// The measure of the empty sequence
+	Identity() Value
+	// The measure of a single element
+	Measure(Value) Value
+	// Combine the measures of two adjacent sequences
+	Combine(Value, Value) Value
+}
+
+// Measurer counting the elements in the tree, as a uint32.
+type sizeMeasurer struct{}
+
+// Measurer for the number of elements, allowing split by position
+var Size Measurer = sizeMeasurer{}
+
+func (sizeMeasurer) Identity() Value {
+	return uint32(0)
+}
+
+func (sizeMeasurer) Measure(Value) Value {
+	return uint32(1)
+}
+
+func (sizeMeasurer) Combine(a, b Value) Value {
+	return a.(uint32) + b.(uint32)
+}
+
+// Return the cached or computed measure of x.
+// Complexity: O(1)
+func measureOf(m Measurer, x Value) Value {
+	if n, ok := x.(*node); ok {
+		return n.Measure
+	}
+	return m.Measure(x)
+}
+
+// Return the combined measure of all elements in d.
+// Complexity: O(1)
+func measureDigit(m Measurer, d []Value) Value {
+	acc := m.Identity()
+	for _, x := range d {
+		acc = m.Combine(acc, measureOf(m, x))
+	}
+	return acc
+}
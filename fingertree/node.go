@@ -0,0 +1,320 @@
+package fingertree
+
+// Internal representation of a (sub)tree.
+// Each level of a deep tree holds nodes of the level above it.
+type tree interface {
+	measure(Measurer) Value
+}
+
+// The empty tree
+type emptyTree struct{}
+
+// A tree holding exactly one element
+type singleTree struct {
+	Element Value
+}
+
+// A tree with a prefix and suffix of 1-4 elements and a nested middle tree
+type deepTree struct {
+	// The cached measure of the entire tree
+	Measure Value
+	// The elements at the front of the tree
+	Prefix []Value
+	// The tree of nodes between prefix and suffix
+	Middle tree
+	// The elements at the back of the tree
+	Suffix []Value
+}
+
+// A 2-3 node with its cached measure
+type node struct {
+	// The cached measure of the elements
+	Measure Value
+	// The 2 or 3 elements stored in this node
+	Elements []Value
+}
+
+// Shared value for the empty tree
+var empty tree = &emptyTree{}
+
+func (t *emptyTree) measure(m Measurer) Value {
+	return m.Identity()
+}
+
+func (t *singleTree) measure(m Measurer) Value {
+	return measureOf(m, t.Element)
+}
+
+func (t *deepTree) measure(m Measurer) Value {
+	return t.Measure
+}
+
+// Create a new node containing elements, caching its measure.
+// Complexity: O(1)
+func newNode(m Measurer, elements ...Value) *node {
+	return &node{
+		Measure:  measureDigit(m, elements),
+		Elements: elements,
+	}
+}
+
+// Create a new deep tree, caching its measure.
+// Complexity: O(1)
+func newDeep(m Measurer, prefix []Value, middle tree, suffix []Value) *deepTree {
+	return &deepTree{
+		Measure: m.Combine(
+			m.Combine(measureDigit(m, prefix), middle.measure(m)),
+			measureDigit(m, suffix),
+		),
+		Prefix: prefix,
+		Middle: middle,
+		Suffix: suffix,
+	}
+}
+
+// Return a new digit with x before the elements of d.
+// Complexity: O(1)
+func cons(x Value, d []Value) []Value {
+	return append(append(make([]Value, 0, len(d)+1), x), d...)
+}
+
+// Return a new digit with x after the elements of d.
+// Complexity: O(1)
+func snoc(d []Value, x Value) []Value {
+	return append(append(make([]Value, 0, len(d)+1), d...), x)
+}
+
+// Push x onto the front of t, returning a new tree.
+// Complexity: O(1) amortized
+func pushFront(m Measurer, t tree, x Value) tree {
+	switch t := t.(type) {
+	case *singleTree:
+		return newDeep(m, []Value{x}, empty, []Value{t.Element})
+	case *deepTree:
+		if len(t.Prefix) == 4 {
+			return newDeep(
+				m,
+				[]Value{x, t.Prefix[0]},
+				pushFront(m, t.Middle, newNode(m, t.Prefix[1], t.Prefix[2], t.Prefix[3])),
+				t.Suffix,
+			)
+		}
+		return newDeep(m, cons(x, t.Prefix), t.Middle, t.Suffix)
+	}
+	return &singleTree{x}
+}
+
+// Push x onto the back of t, returning a new tree.
+// Complexity: O(1) amortized
+func pushBack(m Measurer, t tree, x Value) tree {
+	switch t := t.(type) {
+	case *singleTree:
+		return newDeep(m, []Value{t.Element}, empty, []Value{x})
+	case *deepTree:
+		if len(t.Suffix) == 4 {
+			return newDeep(
+				m,
+				t.Prefix,
+				pushBack(m, t.Middle, newNode(m, t.Suffix[0], t.Suffix[1], t.Suffix[2])),
+				[]Value{t.Suffix[3], x},
+			)
+		}
+		return newDeep(m, t.Prefix, t.Middle, snoc(t.Suffix, x))
+	}
+	return &singleTree{x}
+}
+
+// Return the first element of t and the tree without it.
+// Complexity: O(1) amortized
+func viewFront(m Measurer, t tree) (Value, tree, bool) {
+	switch t := t.(type) {
+	case *singleTree:
+		return t.Element, empty, true
+	case *deepTree:
+		return t.Prefix[0], deepL(m, t.Prefix[1:], t.Middle, t.Suffix), true
+	}
+	return nil, t, false
+}
+
+// Return the last element of t and the tree without it.
+// Complexity: O(1) amortized
+func viewBack(m Measurer, t tree) (Value, tree, bool) {
+	switch t := t.(type) {
+	case *singleTree:
+		return t.Element, empty, true
+	case *deepTree:
+		last := len(t.Suffix) - 1
+		return t.Suffix[last], deepR(m, t.Prefix, t.Middle, t.Suffix[:last]), true
+	}
+	return nil, t, false
+}
+
+// Create a deep tree whose prefix may be empty, borrowing from the middle.
+// Complexity: O(1) amortized
+func deepL(m Measurer, prefix []Value, middle tree, suffix []Value) tree {
+	if len(prefix) > 0 {
+		return newDeep(m, prefix, middle, suffix)
+	}
+
+	x, rest, ok := viewFront(m, middle)
+	if !ok {
+		return digitToTree(m, suffix)
+	}
+
+	return newDeep(m, x.(*node).Elements, rest, suffix)
+}
+
+// Create a deep tree whose suffix may be empty, borrowing from the middle.
+// Complexity: O(1) amortized
+func deepR(m Measurer, prefix []Value, middle tree, suffix []Value) tree {
+	if len(suffix) > 0 {
+		return newDeep(m, prefix, middle, suffix)
+	}
+
+	x, rest, ok := viewBack(m, middle)
+	if !ok {
+		return digitToTree(m, prefix)
+	}
+
+	return newDeep(m, prefix, rest, x.(*node).Elements)
+}
+
+// Build a tree from the elements of a digit.
+// Complexity: O(1)
+func digitToTree(m Measurer, d []Value) tree {
+	acc := empty
+	for _, x := range d {
+		acc = pushBack(m, acc, x)
+	}
+	return acc
+}
+
+// Group 2 or more elements into 2-3 nodes.
+// Complexity: O(n)
+func nodes(m Measurer, xs []Value) []Value {
+	var acc []Value
+	for {
+		switch len(xs) {
+		case 2, 3:
+			return append(acc, newNode(m, xs...))
+		case 4:
+			return append(acc, newNode(m, xs[0], xs[1]), newNode(m, xs[2], xs[3]))
+		}
+		acc = append(acc, newNode(m, xs[0], xs[1], xs[2]))
+		xs = xs[3:]
+	}
+}
+
+// Concatenate two trees with the elements of xs between them.
+// Complexity: O(log(min(n, m)))
+func app3(m Measurer, left tree, xs []Value, right tree) tree {
+	switch l := left.(type) {
+	case *emptyTree:
+		for i := len(xs); i > 0; i-- {
+			right = pushFront(m, right, xs[i-1])
+		}
+		return right
+	case *singleTree:
+		return pushFront(m, app3(m, empty, xs, right), l.Element)
+	}
+
+	switch r := right.(type) {
+	case *emptyTree:
+		for _, x := range xs {
+			left = pushBack(m, left, x)
+		}
+		return left
+	case *singleTree:
+		return pushBack(m, app3(m, left, xs, empty), r.Element)
+	}
+
+	l, r := left.(*deepTree), right.(*deepTree)
+	mid := make([]Value, 0, len(l.Suffix)+len(xs)+len(r.Prefix))
+	mid = append(append(append(mid, l.Suffix...), xs...), r.Prefix...)
+
+	return newDeep(m, l.Prefix, app3(m, l.Middle, nodes(m, mid), r.Middle), r.Suffix)
+}
+
+// Split a digit at the first element where pred holds for the accumulated
+// measure, starting from acc.
+// Complexity: O(1)
+func splitDigit(m Measurer, pred func(Value) bool, acc Value, d []Value) ([]Value, Value, []Value) {
+	last := len(d) - 1
+	for i := 0; i < last; i++ {
+		acc = m.Combine(acc, measureOf(m, d[i]))
+		if pred(acc) {
+			return d[:i], d[i], d[i+1:]
+		}
+	}
+	return d[:last], d[last], nil
+}
+
+// Split a non-empty tree at the first element where pred holds for the
+// accumulated measure, starting from acc.
+// Complexity: O(log(n))
+func splitTree(m Measurer, pred func(Value) bool, acc Value, t tree) (tree, Value, tree) {
+	switch t := t.(type) {
+	case *singleTree:
+		return empty, t.Element, empty
+	case *deepTree:
+		accPrefix := m.Combine(acc, measureDigit(m, t.Prefix))
+		if pred(accPrefix) {
+			l, x, r := splitDigit(m, pred, acc, t.Prefix)
+			return digitToTree(m, l), x, deepL(m, r, t.Middle, t.Suffix)
+		}
+
+		accMiddle := m.Combine(accPrefix, t.Middle.measure(m))
+		if pred(accMiddle) {
+			ml, xs, mr := splitTree(m, pred, accPrefix, t.Middle)
+			l, x, r := splitDigit(
+				m,
+				pred,
+				m.Combine(accPrefix, ml.measure(m)),
+				xs.(*node).Elements,
+			)
+			return deepR(m, t.Prefix, ml, l), x, deepL(m, r, mr, t.Suffix)
+		}
+
+		l, x, r := splitDigit(m, pred, accMiddle, t.Suffix)
+		return deepR(m, t.Prefix, t.Middle, l), x, digitToTree(m, r)
+	}
+	panic("splitTree called on an empty tree")
+}
+
+// Visit each element of t in order until fn returns false.
+// Complexity: O(n)
+func each(t tree, fn func(Value) bool) bool {
+	switch t := t.(type) {
+	case *singleTree:
+		return eachElement(t.Element, fn)
+	case *deepTree:
+		for _, x := range t.Prefix {
+			if !eachElement(x, fn) {
+				return false
+			}
+		}
+		if !each(t.Middle, fn) {
+			return false
+		}
+		for _, x := range t.Suffix {
+			if !eachElement(x, fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Visit x, or the elements beneath x if it is a node.
+// Complexity: O(1)
+func eachElement(x Value, fn func(Value) bool) bool {
+	if n, ok := x.(*node); ok {
+		for _, y := range n.Elements {
+			if !eachElement(y, fn) {
+				return false
+			}
+		}
+		return true
+	}
+	return fn(x)
+}
@@ -0,0 +1,15 @@
+package graph
+
+import (
+	"fmt"
+)
+
+// Error type returned when sorting a graph that contains a cycle
+type Cycle struct {
+	// A node on the cycle
+	Node Value
+}
+
+func (e *Cycle) Error() string {
+	return fmt.Sprintf("graph contains a cycle through %v", e.Node)
+}
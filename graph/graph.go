@@ -0,0 +1,168 @@
+package graph
+
+import (
+	"../hashmap"
+)
+
+// Values usable as graph nodes
+type Value = interface{}
+
+// Persistent directed graph.
+// Adjacency is stored as persistent maps from each node to the set of its
+// successors and predecessors, so each change copies only the paths to the
+// affected nodes. Nodes must be comparable with == and hashable by
+// hashmap.Hash.
+type Graph struct {
+	// The map from each node to the *hashmap.Set of its successors
+	Out *hashmap.Map
+	// The map from each node to the *hashmap.Set of its predecessors
+	In *hashmap.Map
+	// The number of edges in the graph
+	Edges uint32
+}
+
+// Value for the empty graph
+var empty = &Graph{
+	Out: hashmap.New(),
+	In:  hashmap.New(),
+}
+
+// Return a new empty graph.
+// Complexity: O(1)
+func New() *Graph {
+	return empty
+}
+
+// Return the number of nodes in this graph.
+// Complexity: O(1)
+func (g *Graph) NodeCount() uint32 {
+	return g.Out.Count()
+}
+
+// Return the number of edges in this graph.
+// Complexity: O(1)
+func (g *Graph) EdgeCount() uint32 {
+	return g.Edges
+}
+
+// Return true if node is in the graph.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (g *Graph) HasNode(node Value) bool {
+	return g.Out.Contains(node)
+}
+
+// Return true if the graph has an edge from -> to.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (g *Graph) HasEdge(from, to Value) bool {
+	return g.Successors(from).Contains(to)
+}
+
+// Return the set of nodes with an edge from node.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (g *Graph) Successors(node Value) *hashmap.Set {
+	return adjacent(g.Out, node)
+}
+
+// Return the set of nodes with an edge to node.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (g *Graph) Predecessors(node Value) *hashmap.Set {
+	return adjacent(g.In, node)
+}
+
+// Return the set stored for node in an adjacency map.
+// Complexity: O(log32(n))
+func adjacent(m *hashmap.Map, node Value) *hashmap.Set {
+	if set, ok := m.Get(node); ok {
+		return set.(*hashmap.Set)
+	}
+	return hashmap.NewSet()
+}
+
+// Add a node with no edges.
+// A new graph is returned, sharing memory with the original.
+// Adding a node already in the graph returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (g *Graph) AddNode(node Value) *Graph {
+	if g.HasNode(node) {
+		return g
+	}
+	return &Graph{
+		Out:   g.Out.Assoc(node, hashmap.NewSet()),
+		In:    g.In.Assoc(node, hashmap.NewSet()),
+		Edges: g.Edges,
+	}
+}
+
+// Remove a node and every edge to or from it.
+// A new graph is returned, sharing memory with the original.
+// Removing a node not in the graph returns itself.
+// Complexity: O(k*log32(n)) for k incident edges
+func (g *Graph) RemoveNode(node Value) *Graph {
+	if !g.HasNode(node) {
+		return g
+	}
+
+	acc := g
+	g.Successors(node).Each(func(to hashmap.Value) bool {
+		acc = acc.RemoveEdge(node, to)
+		return true
+	})
+	g.Predecessors(node).Each(func(from hashmap.Value) bool {
+		acc = acc.RemoveEdge(from, node)
+		return true
+	})
+
+	return &Graph{
+		Out:   acc.Out.Dissoc(node),
+		In:    acc.In.Dissoc(node),
+		Edges: acc.Edges,
+	}
+}
+
+// Add an edge from -> to, adding either node if missing.
+// A new graph is returned, sharing memory with the original.
+// Adding an edge already in the graph returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (g *Graph) AddEdge(from, to Value) *Graph {
+	if g.HasEdge(from, to) {
+		return g
+	}
+
+	g = g.AddNode(from).AddNode(to)
+	return &Graph{
+		Out:   g.Out.Assoc(from, g.Successors(from).Add(to)),
+		In:    g.In.Assoc(to, g.Predecessors(to).Add(from)),
+		Edges: g.Edges + 1,
+	}
+}
+
+// Remove the edge from -> to, keeping both nodes.
+// A new graph is returned, sharing memory with the original.
+// Removing an edge not in the graph returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (g *Graph) RemoveEdge(from, to Value) *Graph {
+	if !g.HasEdge(from, to) {
+		return g
+	}
+
+	return &Graph{
+		Out:   g.Out.Assoc(from, g.Successors(from).Remove(to)),
+		In:    g.In.Assoc(to, g.Predecessors(to).Remove(from)),
+		Edges: g.Edges - 1,
+	}
+}
+
+// Call fn with each node, until fn returns false.
+// Complexity: O(n)
+func (g *Graph) EachNode(fn func(node Value) bool) {
+	g.Out.Each(func(node, _ hashmap.Value) bool {
+		return fn(node)
+	})
+}
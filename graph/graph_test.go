@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"../hashmap"
+	"testing"
+)
+
+func Deps() *Graph {
+	return New().
+		AddEdge("app", "http").
+		AddEdge("app", "db").
+		AddEdge("http", "net").
+		AddEdge("db", "net").
+		AddEdge("net", "os").
+		AddNode("docs")
+}
+
+func TestAddEdge(t *testing.T) {
+	g := Deps()
+
+	if g.NodeCount() != 6 || g.EdgeCount() != 5 {
+		t.Fatalf(`expected 6 nodes and 5 edges, got %d and %d`, g.NodeCount(), g.EdgeCount())
+	}
+	if !g.HasEdge("app", "db") || g.HasEdge("db", "app") {
+		t.Fatalf(`expected only the edge app -> db`)
+	}
+	if g.Predecessors("net").Count() != 2 || g.Successors("app").Count() != 2 {
+		t.Fatalf(`expected net to have 2 predecessors and app 2 successors`)
+	}
+	if g.AddEdge("app", "db") != g || g.AddNode("docs") != g {
+		t.Fatalf(`expected adding existing nodes and edges to return itself`)
+	}
+}
+
+func TestRemoveEdge(t *testing.T) {
+	g := Deps()
+	cpy := g.RemoveEdge("app", "db")
+
+	if cpy.HasEdge("app", "db") || !g.HasEdge("app", "db") {
+		t.Fatalf(`expected only cpy to lose the edge app -> db`)
+	}
+	if !cpy.HasNode("db") || cpy.EdgeCount() != 4 {
+		t.Fatalf(`expected cpy to keep db with 4 edges, got %d`, cpy.EdgeCount())
+	}
+	if cpy.RemoveEdge("app", "db") != cpy {
+		t.Fatalf(`expected removing a missing edge to return itself`)
+	}
+}
+
+func TestRemoveNode(t *testing.T) {
+	g := Deps().RemoveNode("net")
+
+	if g.HasNode("net") || g.EdgeCount() != 2 {
+		t.Fatalf(`expected net and its 3 edges to be removed, got %d edges`, g.EdgeCount())
+	}
+	if g.Successors("http").Count() != 0 || g.Predecessors("os").Count() != 0 {
+		t.Fatalf(`expected no edges to remain through net`)
+	}
+}
+
+func TestBFS(t *testing.T) {
+	var order []Value
+	Deps().BFS("app", func(node Value) bool {
+		order = append(order, node)
+		return true
+	})
+
+	if len(order) != 5 || order[0] != "app" || order[3] != "net" || order[4] != "os" {
+		t.Fatalf(`expected breadth-first order from app, got %v`, order)
+	}
+}
+
+func TestDFS(t *testing.T) {
+	seen := map[Value]int{}
+	Deps().DFS("http", func(node Value) bool {
+		seen[node] = len(seen)
+		return true
+	})
+
+	if len(seen) != 3 || seen["http"] != 0 || seen["net"] != 1 || seen["os"] != 2 {
+		t.Fatalf(`expected depth-first order http, net, os, got %v`, seen)
+	}
+}
+
+func TestReachable(t *testing.T) {
+	g := Deps()
+
+	if !g.Reachable("app", "os") || !g.Reachable("db", "db") {
+		t.Fatalf(`expected os reachable from app`)
+	}
+	if g.Reachable("os", "app") || g.Reachable("app", "docs") {
+		t.Fatalf(`expected app not reachable from os`)
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := Deps()
+
+	sorted, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatalf(`expected g.TopologicalSort() to be ok, got %s`, err)
+	}
+	if sorted.Count() != 6 {
+		t.Fatalf(`expected 6 sorted nodes, got %d`, sorted.Count())
+	}
+
+	position := map[Value]uint32{}
+	for i := uint32(0); i < sorted.Count(); i++ {
+		node, _ := sorted.Get(i)
+		position[node] = i
+	}
+	g.EachNode(func(from Value) bool {
+		g.Successors(from).Each(func(to hashmap.Value) bool {
+			if position[from] >= position[to] {
+				t.Fatalf(`expected %v before %v`, from, to)
+			}
+			return true
+		})
+		return true
+	})
+
+	if _, err = g.AddEdge("os", "app").TopologicalSort(); err == nil {
+		t.Fatalf(`expected sorting a cyclic graph not to be ok, but was`)
+	}
+}
+
+func TestCycleNodeIsOnTheCycle(t *testing.T) {
+	g := New().AddEdge("a", "b").AddEdge("b", "a").AddEdge("b", "c").AddEdge("c", "d")
+
+	for i := 0; i < 20; i++ {
+		_, err := g.TopologicalSort()
+		cycle, ok := err.(*Cycle)
+		if !ok {
+			t.Fatalf(`expected a Cycle error, got %v`, err)
+		}
+		if cycle.Node != "a" && cycle.Node != "b" {
+			t.Fatalf(`expected a or b to be reported, got %v`, cycle.Node)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package graph
+
+import (
+	"../hashmap"
+	"../vector"
+)
+
+// Call fn with each node reachable from start in breadth-first order,
+// including start itself, until fn returns false.
+// Complexity: O(n + e)
+func (g *Graph) BFS(start Value, fn func(node Value) bool) {
+	if !g.HasNode(start) {
+		return
+	}
+
+	visited := map[Value]bool{start: true}
+	queue := []Value{start}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		if !fn(node) {
+			return
+		}
+
+		g.Successors(node).Each(func(next hashmap.Value) bool {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+			return true
+		})
+	}
+}
+
+// Call fn with each node reachable from start in depth-first pre-order,
+// including start itself, until fn returns false.
+// Complexity: O(n + e)
+func (g *Graph) DFS(start Value, fn func(node Value) bool) {
+	if !g.HasNode(start) {
+		return
+	}
+
+	visited := map[Value]bool{}
+	stack := []Value{start}
+
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if visited[node] {
+			continue
+		}
+		visited[node] = true
+
+		if !fn(node) {
+			return
+		}
+
+		g.Successors(node).Each(func(next hashmap.Value) bool {
+			if !visited[next] {
+				stack = append(stack, next)
+			}
+			return true
+		})
+	}
+}
+
+// Return true if there is a path from -> to.
+// Every node is reachable from itself.
+// Complexity: O(n + e)
+func (g *Graph) Reachable(from, to Value) bool {
+	found := false
+	g.BFS(from, func(node Value) bool {
+		found = node == to
+		return !found
+	})
+	return found
+}
+
+// Return a vector of every node, ordered so that each edge points from an
+// earlier node to a later one.
+// A graph containing a cycle is a Cycle error.
+// Complexity: O(n + e)
+func (g *Graph) TopologicalSort() (*vector.Vector, error) {
+	var (
+		sorted   = vector.New()
+		indegree = make(map[Value]uint32, g.NodeCount())
+		ready    []Value
+	)
+
+	g.EachNode(func(node Value) bool {
+		n := g.Predecessors(node).Count()
+		indegree[node] = n
+		if n == 0 {
+			ready = append(ready, node)
+		}
+		return true
+	})
+
+	for len(ready) > 0 {
+		node := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+		sorted = sorted.Append(node)
+
+		g.Successors(node).Each(func(next hashmap.Value) bool {
+			indegree[next]--
+			if indegree[next] == 0 {
+				ready = append(ready, next)
+			}
+			return true
+		})
+	}
+
+	if sorted.Count() < g.NodeCount() {
+		return nil, &Cycle{g.cycleNode(indegree)}
+	}
+
+	return sorted, nil
+}
+
+// Return a node on a cycle, given the in-degrees left after a topological
+// sort stalls.
+// Every node left unsorted has a predecessor also left unsorted, so walking
+// back through them must repeat a node, and that node is on a cycle.
+// Complexity: O(n + e)
+func (g *Graph) cycleNode(indegree map[Value]uint32) Value {
+	var node Value
+	for n, d := range indegree {
+		if d > 0 {
+			node = n
+			break
+		}
+	}
+
+	seen := map[Value]bool{}
+	for !seen[node] {
+		seen[node] = true
+		g.Predecessors(node).Each(func(prev hashmap.Value) bool {
+			if indegree[prev] > 0 {
+				node = prev
+				return false
+			}
+			return true
+		})
+	}
+
+	return node
+}
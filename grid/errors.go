@@ -0,0 +1,15 @@
+package grid
+
+import (
+	"fmt"
+)
+
+// Error type returned when accessing a cell outside the grid
+type OutOfBounds struct {
+	Row    uint32
+	Column uint32
+}
+
+func (e *OutOfBounds) Error() string {
+	return fmt.Sprintf("cell (%d, %d) out of bounds", e.Row, e.Column)
+}
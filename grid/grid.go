@@ -0,0 +1,194 @@
+package grid
+
+import (
+	"../intmap"
+	"../vector"
+)
+
+// Values storable in the grid
+type Value = interface{}
+
+// Persistent two-dimensional grid of cells.
+// Rows and columns are vectors of stable ids, and cells are stored sparsely
+// in an IntMap keyed by (row id, column id), so empty cells take no space and
+// inserting a row or column never copies any cells.
+type Grid struct {
+	// The id of each row, in order
+	Rows *vector.Vector
+	// The id of each column, in order
+	Columns *vector.Vector
+	// The non-empty cells, keyed by row id << 32 | column id
+	Cells *intmap.IntMap
+	// The id for the next inserted row
+	NextRow uint32
+	// The id for the next inserted column
+	NextColumn uint32
+}
+
+// Return a new grid of empty cells with the given dimensions.
+// Complexity: O(rows + columns)
+func New(rows, columns uint32) *Grid {
+	return &Grid{
+		Rows:       ids(rows),
+		Columns:    ids(columns),
+		Cells:      intmap.New(),
+		NextRow:    rows,
+		NextColumn: columns,
+	}
+}
+
+// Return a vector of the ids 0...n-1.
+// Complexity: O(n)
+func ids(n uint32) *vector.Vector {
+	acc := vector.New()
+	for i := uint32(0); i < n; i++ {
+		acc = acc.Append(i)
+	}
+	return acc
+}
+
+// Return the number of rows in this grid.
+// Complexity: O(1)
+func (g *Grid) RowCount() uint32 {
+	return g.Rows.Count()
+}
+
+// Return the number of columns in this grid.
+// Complexity: O(1)
+func (g *Grid) ColumnCount() uint32 {
+	return g.Columns.Count()
+}
+
+// Return the number of non-empty cells in this grid.
+// Complexity: O(1)
+func (g *Grid) Count() uint32 {
+	return g.Cells.Count()
+}
+
+// Return the key of the cell at (row, col) in Cells.
+// Access to a cell outside the grid is an OutOfBounds error.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (g *Grid) key(row, col uint32) (uint64, error) {
+	r, err := g.Rows.Get(row)
+	if err != nil {
+		return 0, &OutOfBounds{row, col}
+	}
+	c, err := g.Columns.Get(col)
+	if err != nil {
+		return 0, &OutOfBounds{row, col}
+	}
+	return uint64(r.(uint32))<<32 | uint64(c.(uint32)), nil
+}
+
+// Get the value of the cell at (row, col), or nil if it is empty.
+// Access to a cell outside the grid is an OutOfBounds error.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (g *Grid) Get(row, col uint32) (Value, error) {
+	k, err := g.key(row, col)
+	if err != nil {
+		return nil, err
+	}
+
+	v, _ := g.Cells.Lookup(k)
+	return v, nil
+}
+
+// Set the value of the cell at (row, col).
+// Setting a cell to nil empties it.
+// Attempts to set a cell outside the grid is an OutOfBounds error.
+// A new grid is returned, sharing memory with the original.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (g *Grid) Set(row, col uint32, value Value) (*Grid, error) {
+	k, err := g.key(row, col)
+	if err != nil {
+		return nil, err
+	}
+
+	var cells *intmap.IntMap
+	if value == nil {
+		cells = g.Cells.Delete(k)
+	} else {
+		cells = g.Cells.Insert(k, value)
+	}
+
+	return &Grid{
+		Rows:       g.Rows,
+		Columns:    g.Columns,
+		Cells:      cells,
+		NextRow:    g.NextRow,
+		NextColumn: g.NextColumn,
+	}, nil
+}
+
+// Insert an empty row before row, or at the end if row == RowCount().
+// Attempts to insert at row > RowCount() is an OutOfBounds error.
+// A new grid is returned, sharing memory with the original.
+// Complexity: O(min(row, RowCount() - row))
+func (g *Grid) InsertRow(row uint32) (*Grid, error) {
+	rows, err := g.Rows.Insert(row, g.NextRow)
+	if err != nil {
+		return nil, &OutOfBounds{row, 0}
+	}
+
+	return &Grid{
+		Rows:       rows,
+		Columns:    g.Columns,
+		Cells:      g.Cells,
+		NextRow:    g.NextRow + 1,
+		NextColumn: g.NextColumn,
+	}, nil
+}
+
+// Insert an empty column before col, or at the end if col == ColumnCount().
+// Attempts to insert at col > ColumnCount() is an OutOfBounds error.
+// A new grid is returned, sharing memory with the original.
+// Complexity: O(min(col, ColumnCount() - col))
+func (g *Grid) InsertColumn(col uint32) (*Grid, error) {
+	columns, err := g.Columns.Insert(col, g.NextColumn)
+	if err != nil {
+		return nil, &OutOfBounds{0, col}
+	}
+
+	return &Grid{
+		Rows:       g.Rows,
+		Columns:    columns,
+		Cells:      g.Cells,
+		NextRow:    g.NextRow,
+		NextColumn: g.NextColumn + 1,
+	}, nil
+}
+
+// Return a vector of the cells in row, with nil for empty cells.
+// Access to a row outside the grid is an OutOfBounds error.
+// Complexity: O(ColumnCount())
+func (g *Grid) Row(row uint32) (*vector.Vector, error) {
+	if row >= g.RowCount() {
+		return nil, &OutOfBounds{row, 0}
+	}
+
+	acc := vector.New()
+	for col := uint32(0); col < g.ColumnCount(); col++ {
+		v, _ := g.Get(row, col)
+		acc = acc.Append(v)
+	}
+	return acc, nil
+}
+
+// Return a vector of the cells in col, with nil for empty cells.
+// Access to a column outside the grid is an OutOfBounds error.
+// Complexity: O(RowCount())
+func (g *Grid) Column(col uint32) (*vector.Vector, error) {
+	if col >= g.ColumnCount() {
+		return nil, &OutOfBounds{0, col}
+	}
+
+	acc := vector.New()
+	for row := uint32(0); row < g.RowCount(); row++ {
+		v, _ := g.Get(row, col)
+		acc = acc.Append(v)
+	}
+	return acc, nil
+}
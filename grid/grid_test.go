@@ -0,0 +1,102 @@
+package grid
+
+import (
+	"../vector"
+	"testing"
+)
+
+func AssertCells(t *testing.T, vec *vector.Vector, cells []Value) {
+	if vec.Count() != uint32(len(cells)) {
+		t.Fatalf(`expected %d cells, got %d`, len(cells), vec.Count())
+	}
+	for i, c := range cells {
+		if v, _ := vec.Get(uint32(i)); v != c {
+			t.Fatalf(`expected cell %d == %v, got %v`, i, c, v)
+		}
+	}
+}
+
+func TestGetAndSet(t *testing.T) {
+	g := New(3, 4)
+
+	if v, err := g.Get(2, 3); err != nil || v != nil {
+		t.Fatalf(`expected g.Get(2, 3) to be empty, got %v, %s`, v, err)
+	}
+
+	cpy, err := g.Set(1, 2, "x")
+	if err != nil {
+		t.Fatalf(`expected g.Set(1, 2) to be ok, got %s`, err)
+	}
+	if v, _ := cpy.Get(1, 2); v != "x" {
+		t.Fatalf(`expected cpy.Get(1, 2) == "x", got %v`, v)
+	}
+	if v, _ := g.Get(1, 2); v != nil {
+		t.Fatalf(`expected g.Get(1, 2) to be empty, got %v`, v)
+	}
+	if cpy.Count() != 1 || g.Count() != 0 {
+		t.Fatalf(`expected only cpy to have a non-empty cell`)
+	}
+
+	cpy, _ = cpy.Set(1, 2, nil)
+	if cpy.Count() != 0 {
+		t.Fatalf(`expected setting nil to empty the cell, got %d cells`, cpy.Count())
+	}
+}
+
+func TestOutOfBounds(t *testing.T) {
+	g := New(3, 4)
+
+	if _, err := g.Get(3, 0); err == nil {
+		t.Fatalf(`expected g.Get(3, 0) not to be ok, but was`)
+	}
+	if _, err := g.Set(0, 4, "x"); err == nil {
+		t.Fatalf(`expected g.Set(0, 4) not to be ok, but was`)
+	}
+	if _, err := g.InsertRow(4); err == nil {
+		t.Fatalf(`expected g.InsertRow(4) not to be ok, but was`)
+	}
+	if _, err := g.Column(4); err == nil {
+		t.Fatalf(`expected g.Column(4) not to be ok, but was`)
+	}
+}
+
+func TestInsertRow(t *testing.T) {
+	g := New(3, 2)
+	g, _ = g.Set(0, 0, "a")
+	g, _ = g.Set(1, 0, "b")
+	g, _ = g.Set(2, 1, "c")
+
+	for _, at := range []uint32{0, 1, 3} {
+		cpy, err := g.InsertRow(at)
+		if err != nil {
+			t.Fatalf(`expected g.InsertRow(%d) to be ok, got %s`, at, err)
+		}
+		if cpy.RowCount() != 4 {
+			t.Fatalf(`expected 4 rows, got %d`, cpy.RowCount())
+		}
+
+		expected := []Value{"a", "b", nil}
+		expected = append(expected[:at], append([]Value{nil}, expected[at:]...)...)
+
+		col, _ := cpy.Column(0)
+		AssertCells(t, col, expected)
+	}
+
+	col, _ := g.Column(0)
+	AssertCells(t, col, []Value{"a", "b", nil})
+}
+
+func TestInsertColumn(t *testing.T) {
+	g := New(1, 3)
+	g, _ = g.Set(0, 0, "a")
+	g, _ = g.Set(0, 2, "c")
+
+	g, err := g.InsertColumn(1)
+	if err != nil {
+		t.Fatalf(`expected g.InsertColumn(1) to be ok, got %s`, err)
+	}
+	g, _ = g.Set(0, 1, "b")
+
+	row, _ := g.Row(0)
+	AssertCells(t, row, []Value{"a", "b", nil, "c"})
+}
@@ -0,0 +1,47 @@
+package hashmap
+
+const (
+	// The default number of keys above which a map is promoted to a trie
+	THRESHOLD = 8
+)
+
+// Find the index of key in a flat array of alternating keys and values.
+// Complexity: O(n)
+func findPair(pairs []Value, key Value) (int, bool) {
+	for i := 0; i < len(pairs); i += 2 {
+		if pairs[i] == key {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Return a copy of pairs with key set to value.
+// Complexity: O(n)
+func assocPair(pairs []Value, key, value Value) []Value {
+	if i, ok := findPair(pairs, key); ok {
+		into := append([]Value(nil), pairs...)
+		into[i+1] = value
+		return into
+	}
+
+	into := make([]Value, 0, len(pairs)+2)
+	return append(append(into, pairs...), key, value)
+}
+
+// Return a copy of pairs without the pair at index i.
+// Complexity: O(n)
+func dissocPair(pairs []Value, i int) []Value {
+	into := make([]Value, 0, len(pairs)-2)
+	return append(append(into, pairs[:i]...), pairs[i+2:]...)
+}
+
+// Build a trie containing the entries of a flat array of pairs.
+// Complexity: O(n)
+func pairsToTrie(pairs []Value) *Node {
+	root := &Node{}
+	for i := 0; i < len(pairs); i += 2 {
+		root, _ = root.assoc(0, &Entry{Hash(pairs[i]), pairs[i], pairs[i+1]}, nil)
+	}
+	return root
+}
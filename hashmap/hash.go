@@ -0,0 +1,137 @@
+package hashmap
+
+import (
+	"hash/fnv"
+	"math"
+	"reflect"
+)
+
+// Keys implementing Hasher provide their own hash code.
+// Keys which are == must return the same hash.
+type Hasher interface {
+	Hash() uint32
+}
+
+// Return the hash code of key.
+// Keys must be comparable with ==.
+// Complexity: O(1) for numeric keys, O(len) for strings
+func Hash(key Value) uint32 {
+	switch k := key.(type) {
+	case Hasher:
+		return k.Hash()
+	case string:
+		return hashString(k)
+	case int:
+		return mix(uint64(k))
+	case int8:
+		return mix(uint64(k))
+	case int16:
+		return mix(uint64(k))
+	case int32:
+		return mix(uint64(k))
+	case int64:
+		return mix(uint64(k))
+	case uint:
+		return mix(uint64(k))
+	case uint8:
+		return mix(uint64(k))
+	case uint16:
+		return mix(uint64(k))
+	case uint32:
+		return mix(uint64(k))
+	case uint64:
+		return mix(k)
+	case uintptr:
+		return mix(uint64(k))
+	case float32:
+		return hashFloat(float64(k))
+	case float64:
+		return hashFloat(k)
+	case bool:
+		if k {
+			return 1
+		}
+		return 0
+	case nil:
+		return 0
+	}
+	return hashValue(reflect.ValueOf(key))
+}
+
+// Return the hash code of v, found by reflection.
+// Pointers, channels and functions hash by address, as == compares them by
+// identity, and structs, arrays and interfaces hash by their contents.
+// Complexity: O(size of v)
+func hashValue(v reflect.Value) uint32 {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return mix(uint64(v.Pointer()))
+	case reflect.String:
+		return hashString(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return mix(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return mix(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return hashFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		return combine(hashFloat(real(v.Complex())), hashFloat(imag(v.Complex())))
+	case reflect.Bool:
+		if v.Bool() {
+			return 1
+		}
+		return 0
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		return hashValue(v.Elem())
+	case reflect.Struct:
+		h := hashString(v.Type().String())
+		for i := 0; i < v.NumField(); i++ {
+			h = combine(h, hashValue(v.Field(i)))
+		}
+		return h
+	case reflect.Array:
+		h := hashString(v.Type().String())
+		for i := 0; i < v.Len(); i++ {
+			h = combine(h, hashValue(v.Index(i)))
+		}
+		return h
+	}
+	return hashString(v.Type().String())
+}
+
+// Return the hash code of f, treating 0 and -0 as equal, since they are ==.
+// Complexity: O(1)
+func hashFloat(f float64) uint32 {
+	if f == 0 {
+		return 0
+	}
+	return mix(math.Float64bits(f))
+}
+
+// Return the hash code of a sequence of two hash codes.
+// Complexity: O(1)
+func combine(h, x uint32) uint32 {
+	return h*31 + x
+}
+
+// Return the FNV-1a hash of s.
+// Complexity: O(len(s))
+func hashString(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
+
+// Scramble the bits of x, so nearby integers spread across the trie.
+// Complexity: O(1)
+func mix(x uint64) uint32 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return uint32(x)
+}
@@ -0,0 +1,208 @@
+package hashmap
+
+// Values storable in the map
+type Value = interface{}
+
+// Function combining the values of a key present in both maps
+type Combiner func(key, a, b Value) Value
+
+// Persistent hash map.
+// Small maps store their keys and values in a flat array, searched linearly.
+// Once a map holds more than Threshold keys it is promoted to a hash array
+// mapped trie as described by Phil Bagwell, using the same 32-way
+// partitioning as vector.Vector. Keys must be comparable with == and
+// hashable by Hash.
+type Map struct {
+	// Alternating keys and values, while the map is small
+	Array []Value
+	// The root node of the trie, once the map is promoted
+	Root *Node
+	// The number of keys above which the map is promoted
+	Threshold uint32
+}
+
+// Value for the empty map
+var empty = &Map{Threshold: THRESHOLD}
+
+// Return a new map containing the key/value pairs in kvs...
+// Complexity: O(n)
+func New(kvs ...Value) *Map {
+	return NewWithThreshold(THRESHOLD, kvs...)
+}
+
+// Return a new map containing the key/value pairs in kvs..., which stays a
+// flat array until it holds more than threshold keys.
+// Complexity: O(n)
+func NewWithThreshold(threshold uint32, kvs ...Value) *Map {
+	if len(kvs)%2 != 0 {
+		panic("hashmap.New requires an even number of arguments")
+	}
+
+	acc := &Map{Threshold: threshold}
+	for i := 0; i < len(kvs); i += 2 {
+		acc = acc.Assoc(kvs[i], kvs[i+1])
+	}
+	return acc
+}
+
+// Return true if the map is stored as a flat array.
+// Complexity: O(1)
+func (m *Map) isArray() bool {
+	return m.Root == nil
+}
+
+// Return the root of the map, building a trie if the map is a flat array.
+// Complexity: O(1) for tries, O(n) for arrays
+func (m *Map) trie() *Node {
+	if m.isArray() {
+		return pairsToTrie(m.Array)
+	}
+	return m.Root
+}
+
+// Return the number of keys in this map.
+// Complexity: O(1)
+func (m *Map) Count() uint32 {
+	if m.isArray() {
+		return uint32(len(m.Array) / 2)
+	}
+	return m.Root.Size
+}
+
+// Get the value stored for key.
+// Returns false if key is not in the map.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (m *Map) Get(key Value) (Value, bool) {
+	if m.isArray() {
+		if i, ok := findPair(m.Array, key); ok {
+			return m.Array[i+1], true
+		}
+		return nil, false
+	}
+
+	e, ok := m.Root.get(0, Hash(key), key)
+	if !ok {
+		return nil, false
+	}
+	return e.Value, true
+}
+
+// Get the value stored for key, as Get.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (m *Map) Lookup(key Value) (Value, bool) {
+	return m.Get(key)
+}
+
+// Return true if key is in the map.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (m *Map) Contains(key Value) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Associate key with value, replacing any existing value.
+// A new map is returned, sharing memory with the original.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (m *Map) Assoc(key, value Value) *Map {
+	if m.isArray() {
+		pairs := assocPair(m.Array, key, value)
+		if uint32(len(pairs)/2) <= m.Threshold {
+			return &Map{Array: pairs, Threshold: m.Threshold}
+		}
+		return &Map{Root: pairsToTrie(pairs), Threshold: m.Threshold}
+	}
+
+	root, _ := m.Root.assoc(0, &Entry{Hash(key), key, value}, nil)
+	return &Map{Root: root, Threshold: m.Threshold}
+}
+
+// Remove key from the map.
+// A new map is returned, sharing memory with the original.
+// Removing a key that is not in the map returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (m *Map) Dissoc(key Value) *Map {
+	if m.isArray() {
+		if i, ok := findPair(m.Array, key); ok {
+			return &Map{Array: dissocPair(m.Array, i), Threshold: m.Threshold}
+		}
+		return m
+	}
+
+	switch root := m.Root.dissoc(0, Hash(key), key).(type) {
+	case *Node:
+		if root == m.Root {
+			return m
+		}
+		return &Map{Root: root, Threshold: m.Threshold}
+	case nil:
+		return &Map{Threshold: m.Threshold}
+	default:
+		// a single leaf remains, which must stay beneath a root node
+		bit, _ := slot(0, hashOf(root), 0)
+		return &Map{
+			Root: &Node{
+				Bitmap:   bit,
+				Children: []Value{root},
+				Size:     sizeOf(root),
+			},
+			Threshold: m.Threshold,
+		}
+	}
+}
+
+// Return the keys of this map and other.
+// Values of keys in both maps are combined with fn(key, mine, theirs), or
+// taken from other when fn is nil.
+// A new map is returned, sharing memory with both originals.
+// Complexity: O(n + m)
+func (m *Map) Merge(other *Map, fn Combiner) *Map {
+	if other.Count() == 0 {
+		return m
+	}
+
+	if m.isArray() && other.isArray() {
+		acc := m
+		for i := 0; i < len(other.Array); i += 2 {
+			key, value := other.Array[i], other.Array[i+1]
+			if mine, ok := acc.Get(key); ok && fn != nil {
+				value = fn(key, mine, value)
+			}
+			acc = acc.Assoc(key, value)
+		}
+		return acc
+	}
+
+	var res resolver
+	if fn != nil {
+		res = resolver(fn)
+	}
+
+	root, _ := merge(0, m.trie(), other.trie(), res)
+	if root == m.Root {
+		return m
+	}
+	return &Map{Root: root, Threshold: m.Threshold}
+}
+
+// Call fn with each key and value, until fn returns false.
+// The order of iteration is unspecified but stable for a given map.
+// Complexity: O(n)
+func (m *Map) Each(fn func(key, value Value) bool) {
+	if m.isArray() {
+		for i := 0; i < len(m.Array); i += 2 {
+			if !fn(m.Array[i], m.Array[i+1]) {
+				return
+			}
+		}
+		return
+	}
+
+	eachEntry(m.Root, func(e *Entry) bool {
+		return fn(e.Key, e.Value)
+	})
+}
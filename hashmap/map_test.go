@@ -0,0 +1,262 @@
+package hashmap
+
+import (
+	"math"
+	"testing"
+)
+
+// Key type whose hash always collides
+type collider int
+
+func (c collider) Hash() uint32 {
+	return 7
+}
+
+func AssertContains(t *testing.T, m *Map, elems map[Value]Value) {
+	for k, v := range elems {
+		x, ok := m.Get(k)
+		if !ok {
+			t.Fatalf(`expected m.Get(%v) to be ok, but was not`, k)
+		}
+		if x != v {
+			t.Fatalf(`expected m.Get(%v) == %v, got %v`, k, v, x)
+		}
+	}
+}
+
+func AssertEach(t *testing.T, m *Map) {
+	var n uint32
+	m.Each(func(k, v Value) bool {
+		if x, ok := m.Get(k); !ok || x != v {
+			t.Fatalf(`expected m.Each to visit only stored entries, got %v => %v`, k, v)
+		}
+		n++
+		return true
+	})
+	if n != m.Count() {
+		t.Fatalf(`expected m.Each to visit %d entries, got %d`, m.Count(), n)
+	}
+}
+
+func TestNewWithArgs(t *testing.T) {
+	m := New("a", 1, "b", 2, 3, "c")
+
+	AssertContains(t, m, map[Value]Value{"a": 1, "b": 2, 3: "c"})
+	if m.Count() != 3 {
+		t.Fatalf(`expected m.Count() == 3, got %d`, m.Count())
+	}
+	if _, ok := m.Get("c"); ok {
+		t.Fatalf(`expected m.Get("c") not to be ok, but was`)
+	}
+}
+
+func TestAssoc(t *testing.T) {
+	m := New()
+	for i := 0; i < 10000; i++ {
+		m = m.Assoc(i, i*2)
+	}
+	cpy := m.Assoc(5, "five").Assoc("new", 1)
+
+	for i := 0; i < 10000; i++ {
+		AssertContains(t, m, map[Value]Value{i: i * 2})
+	}
+	AssertContains(t, cpy, map[Value]Value{5: "five", "new": 1, 6: 12})
+	AssertContains(t, m, map[Value]Value{5: 10})
+
+	if m.Count() != 10000 || cpy.Count() != 10001 {
+		t.Fatalf(`expected counts 10000 and 10001, got %d and %d`, m.Count(), cpy.Count())
+	}
+	AssertEach(t, cpy)
+}
+
+func TestDissoc(t *testing.T) {
+	m := New()
+	for i := 0; i < 2000; i++ {
+		m = m.Assoc(i, i)
+	}
+
+	cpy := m
+	for i := 0; i < 2000; i += 2 {
+		cpy = cpy.Dissoc(i)
+	}
+
+	if cpy.Count() != 1000 {
+		t.Fatalf(`expected cpy.Count() == 1000, got %d`, cpy.Count())
+	}
+	if cpy.Contains(10) || !cpy.Contains(11) || !m.Contains(10) {
+		t.Fatalf(`expected only the even keys removed from cpy`)
+	}
+	if cpy.Dissoc(10) != cpy {
+		t.Fatalf(`expected dissociating a missing key to return itself`)
+	}
+	AssertEach(t, cpy)
+
+	for i := 1; i < 2000; i += 2 {
+		cpy = cpy.Dissoc(i)
+	}
+	if cpy.Count() != 0 {
+		t.Fatalf(`expected cpy.Count() == 0, got %d`, cpy.Count())
+	}
+}
+
+func TestCollisions(t *testing.T) {
+	m := NewWithThreshold(0, collider(1), "a", collider(2), "b", collider(3), "c", 4, "d")
+
+	AssertContains(t, m, map[Value]Value{collider(1): "a", collider(2): "b", collider(3): "c", 4: "d"})
+
+	cpy := m.Assoc(collider(2), "B").Dissoc(collider(1))
+	AssertContains(t, cpy, map[Value]Value{collider(2): "B", collider(3): "c"})
+	if cpy.Contains(collider(1)) || cpy.Count() != 3 {
+		t.Fatalf(`expected collider(1) removed from cpy`)
+	}
+
+	cpy = cpy.Dissoc(collider(3)).Dissoc(4)
+	AssertContains(t, cpy, map[Value]Value{collider(2): "B"})
+	if cpy.Count() != 1 {
+		t.Fatalf(`expected cpy.Count() == 1, got %d`, cpy.Count())
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := NewWithThreshold(0, "x", 1, "y", 2, collider(1), 3)
+	b := NewWithThreshold(0, "y", 20, "z", 30, collider(1), 40, collider(2), 50)
+
+	sum := a.Merge(b, func(k, x, y Value) Value {
+		return x.(int) + y.(int)
+	})
+	AssertContains(t, sum, map[Value]Value{"x": 1, "y": 22, "z": 30, collider(1): 43, collider(2): 50})
+	if sum.Count() != 5 {
+		t.Fatalf(`expected sum.Count() == 5, got %d`, sum.Count())
+	}
+	AssertEach(t, sum)
+
+	right := a.Merge(b, nil)
+	AssertContains(t, right, map[Value]Value{"x": 1, "y": 20, collider(1): 40})
+}
+
+func TestMergeSharesStructure(t *testing.T) {
+	m := New()
+	for i := 0; i < 5000; i++ {
+		m = m.Assoc(i, i)
+	}
+	a := m.Assoc(-1, -1)
+
+	if m.Merge(m, nil) != m {
+		t.Fatalf(`expected merging a map with itself to return itself`)
+	}
+	if merged := a.Merge(m, nil); merged != a || merged.Count() != 5001 {
+		t.Fatalf(`expected merging a subset to return the superset`)
+	}
+}
+
+func TestSet(t *testing.T) {
+	set := NewSet(1, 2, 3, 2)
+	cpy := set.Add(4).Remove(1)
+
+	if set.Count() != 3 || cpy.Count() != 3 {
+		t.Fatalf(`expected counts 3 and 3, got %d and %d`, set.Count(), cpy.Count())
+	}
+	if !set.Contains(1) || cpy.Contains(1) || !cpy.Contains(4) {
+		t.Fatalf(`expected cpy to differ from set by 1 and 4`)
+	}
+	if set.Add(2) != set || set.Remove(9) != set {
+		t.Fatalf(`expected no-op updates to return the set itself`)
+	}
+
+	union := set.Union(cpy)
+	if union.Count() != 4 {
+		t.Fatalf(`expected union.Count() == 4, got %d`, union.Count())
+	}
+}
+
+func TestSmallMapsAreArrays(t *testing.T) {
+	m := New()
+	for i := 0; i < THRESHOLD; i++ {
+		m = m.Assoc(i, i)
+	}
+	if m.Root != nil || len(m.Array) != 2*THRESHOLD {
+		t.Fatalf(`expected a map of %d keys to be a flat array`, THRESHOLD)
+	}
+	AssertEach(t, m)
+
+	cpy := m.Assoc(THRESHOLD, THRESHOLD)
+	if cpy.Root == nil || cpy.Array != nil {
+		t.Fatalf(`expected a map of %d keys to be promoted to a trie`, THRESHOLD+1)
+	}
+	for i := 0; i <= THRESHOLD; i++ {
+		AssertContains(t, cpy, map[Value]Value{i: i})
+	}
+	if m.Count() != THRESHOLD || cpy.Count() != THRESHOLD+1 {
+		t.Fatalf(`expected counts %d and %d, got %d and %d`, THRESHOLD, THRESHOLD+1, m.Count(), cpy.Count())
+	}
+
+	small := m.Assoc(3, "three").Dissoc(4)
+	AssertContains(t, small, map[Value]Value{3: "three", 5: 5})
+	if small.Contains(4) || small.Count() != THRESHOLD-1 {
+		t.Fatalf(`expected 4 to be removed from the array map`)
+	}
+}
+
+func TestCustomThreshold(t *testing.T) {
+	m := NewWithThreshold(2, "a", 1, "b", 2)
+	if m.Root != nil {
+		t.Fatalf(`expected a map of 2 keys to be a flat array`)
+	}
+
+	cpy := m.Assoc("c", 3)
+	if cpy.Root == nil {
+		t.Fatalf(`expected a map of 3 keys to be promoted to a trie`)
+	}
+	if cpy.Dissoc("c").Dissoc("b").Dissoc("a").Threshold != 2 {
+		t.Fatalf(`expected the threshold to be kept across updates`)
+	}
+}
+
+func TestMergeArrayAndTrie(t *testing.T) {
+	small := New("a", 1, "b", 2)
+	large := New()
+	for i := 0; i < 100; i++ {
+		large = large.Assoc(i, i)
+	}
+	large = large.Assoc("a", 10)
+
+	sum := func(k, x, y Value) Value {
+		return x.(int) + y.(int)
+	}
+
+	merged := small.Merge(large, sum)
+	AssertContains(t, merged, map[Value]Value{"a": 11, "b": 2, 50: 50})
+	if merged.Count() != 102 {
+		t.Fatalf(`expected merged.Count() == 102, got %d`, merged.Count())
+	}
+
+	both := small.Merge(New("b", 3, "c", 4), sum)
+	AssertContains(t, both, map[Value]Value{"a": 1, "b": 5, "c": 4})
+	if both.Root != nil {
+		t.Fatalf(`expected merging small maps to stay a flat array`)
+	}
+}
+
+// Key type compared by pointer identity
+type box struct {
+	N int
+}
+
+func TestPointerKeysHashByAddress(t *testing.T) {
+	for _, threshold := range []uint32{0, THRESHOLD} {
+		k := &box{1}
+		m := NewWithThreshold(threshold, k, "a", &box{1}, "b")
+
+		k.N = 2
+		if v, ok := m.Get(k); !ok || v != "a" {
+			t.Fatalf(`expected m.Get(k) == "a" after changing *k, got %v, %v`, v, ok)
+		}
+		if _, ok := m.Get(&box{1}); ok {
+			t.Fatalf(`expected a new pointer not to be found`)
+		}
+	}
+
+	if Hash(box{3}) != Hash(box{3}) || Hash(0.0) != Hash(math.Copysign(0, -1)) {
+		t.Fatalf(`expected == values to hash equally`)
+	}
+}
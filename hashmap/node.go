@@ -0,0 +1,384 @@
+package hashmap
+
+import (
+	"../vector"
+	"math/bits"
+)
+
+// A key and value stored in the trie
+type Entry struct {
+	// The hash code of Key
+	Hash uint32
+	// The key of the entry
+	Key Value
+	// The value of the entry
+	Value Value
+}
+
+// Entries whose keys share the same full hash code
+type collision struct {
+	// The hash code shared by every entry
+	Hash uint32
+	// The entries, in insertion order
+	Entries []*Entry
+}
+
+// Representation of a hash array mapped trie node.
+// Each node consumes vector.BITS bits of the hash. Bitmap records which of
+// the vector.SIZE slots are occupied, and Children holds only the occupied
+// slots, each being an *Entry, a *collision or a *Node.
+type Node struct {
+	// The occupied slots of this node
+	Bitmap uint32
+	// The contents of the occupied slots, in slot order
+	Children []Value
+	// The number of entries beneath this node
+	Size uint32
+}
+
+// Function combining the value already in the trie with an incoming value
+type resolver func(key, old, new Value) Value
+
+// Resolve the value for key with fn, preferring new when fn is nil.
+// Complexity: O(1)
+func resolve(fn resolver, key, old, new Value) Value {
+	if fn == nil {
+		return new
+	}
+	return fn(key, old, new)
+}
+
+// Return the bit for hash at shift, and its index in a node with bitmap.
+// Complexity: O(1)
+func slot(bitmap, hash, shift uint32) (uint32, int) {
+	bit := uint32(1) << ((hash >> shift) & vector.MASK)
+	return bit, bits.OnesCount32(bitmap & (bit - 1))
+}
+
+// Return the number of entries in a child.
+// Complexity: O(1)
+func sizeOf(child Value) uint32 {
+	switch c := child.(type) {
+	case *Node:
+		return c.Size
+	case *collision:
+		return uint32(len(c.Entries))
+	}
+	return 1
+}
+
+// Return the hash shared by the entries of a leaf child.
+// Complexity: O(1)
+func hashOf(child Value) uint32 {
+	if c, ok := child.(*collision); ok {
+		return c.Hash
+	}
+	return child.(*Entry).Hash
+}
+
+// Visit each entry beneath child until fn returns false.
+// Complexity: O(n)
+func eachEntry(child Value, fn func(*Entry) bool) bool {
+	switch c := child.(type) {
+	case *Node:
+		for _, x := range c.Children {
+			if !eachEntry(x, fn) {
+				return false
+			}
+		}
+		return true
+	case *collision:
+		for _, e := range c.Entries {
+			if !fn(e) {
+				return false
+			}
+		}
+		return true
+	}
+	return fn(child.(*Entry))
+}
+
+// Find the entry for key beneath node.
+// Complexity: O(log32(n))
+func (node *Node) get(shift, hash uint32, key Value) (*Entry, bool) {
+	for {
+		bit, idx := slot(node.Bitmap, hash, shift)
+		if node.Bitmap&bit == 0 {
+			return nil, false
+		}
+
+		switch c := node.Children[idx].(type) {
+		case *Node:
+			node = c
+			shift += vector.BITS
+		case *collision:
+			if c.Hash == hash {
+				for _, e := range c.Entries {
+					if e.Key == key {
+						return e, true
+					}
+				}
+			}
+			return nil, false
+		case *Entry:
+			return c, c.Hash == hash && c.Key == key
+		}
+	}
+}
+
+// Create a node at shift containing two leaf children with different hashes.
+// Complexity: O(1)
+func mergeLeaves(shift uint32, a, b Value) *Node {
+	ha, hb := hashOf(a), hashOf(b)
+	ia, ib := (ha>>shift)&vector.MASK, (hb>>shift)&vector.MASK
+
+	node := &Node{
+		Bitmap: 1<<ia | 1<<ib,
+		Size:   sizeOf(a) + sizeOf(b),
+	}
+	switch {
+	case ia == ib:
+		node.Children = []Value{mergeLeaves(shift+vector.BITS, a, b)}
+	case ia < ib:
+		node.Children = []Value{a, b}
+	default:
+		node.Children = []Value{b, a}
+	}
+	return node
+}
+
+// Store e in a slot at shift currently holding child, resolving an existing
+// value with fn, or replacing it when fn is nil.
+// Returns the new contents of the slot, and true if the key was added.
+// Returns child itself if e is already stored there and fn is nil.
+// Complexity: O(log32(n))
+func assocChild(shift uint32, child Value, e *Entry, fn resolver) (Value, bool) {
+	switch c := child.(type) {
+	case *Node:
+		return c.assoc(shift, e, fn)
+	case *collision:
+		if c.Hash != e.Hash {
+			return mergeLeaves(shift, c, e), true
+		}
+		entries := append([]*Entry(nil), c.Entries...)
+		for i, x := range entries {
+			if x == e && fn == nil {
+				return c, false
+			}
+			if x.Key == e.Key {
+				entries[i] = &Entry{e.Hash, e.Key, resolve(fn, e.Key, x.Value, e.Value)}
+				return &collision{c.Hash, entries}, false
+			}
+		}
+		return &collision{c.Hash, append(entries, e)}, true
+	case *Entry:
+		switch {
+		case c == e && fn == nil:
+			return c, false
+		case c.Hash == e.Hash && c.Key == e.Key:
+			return &Entry{e.Hash, e.Key, resolve(fn, e.Key, c.Value, e.Value)}, false
+		case c.Hash == e.Hash:
+			return &collision{c.Hash, []*Entry{c, e}}, true
+		}
+		return mergeLeaves(shift, c, e), true
+	}
+	return e, true
+}
+
+// Store e beneath node, returning a new node.
+// Only the path to e is copied.
+// Complexity: O(log32(n))
+func (node *Node) assoc(shift uint32, e *Entry, fn resolver) (*Node, bool) {
+	bit, idx := slot(node.Bitmap, e.Hash, shift)
+
+	if node.Bitmap&bit == 0 {
+		children := make([]Value, 0, len(node.Children)+1)
+		children = append(children, node.Children[:idx]...)
+		children = append(children, e)
+		children = append(children, node.Children[idx:]...)
+
+		return &Node{
+			Bitmap:   node.Bitmap | bit,
+			Children: children,
+			Size:     node.Size + 1,
+		}, true
+	}
+
+	child, added := assocChild(shift+vector.BITS, node.Children[idx], e, fn)
+	if child == node.Children[idx] {
+		return node, false
+	}
+
+	into := &Node{
+		Bitmap:   node.Bitmap,
+		Children: append([]Value(nil), node.Children...),
+		Size:     node.Size,
+	}
+	into.Children[idx] = child
+	if added {
+		into.Size++
+	}
+	return into, added
+}
+
+// Remove key from the slot at shift holding child.
+// Returns the new contents of the slot, which is nil when empty, or a leaf
+// when only one leaf remains beneath it.
+// Returns child itself if key is not present.
+// Complexity: O(log32(n))
+func dissocChild(shift uint32, child Value, hash uint32, key Value) Value {
+	switch c := child.(type) {
+	case *Node:
+		return c.dissoc(shift, hash, key)
+	case *collision:
+		if c.Hash != hash {
+			return c
+		}
+		for i, x := range c.Entries {
+			if x.Key == key {
+				if len(c.Entries) == 2 {
+					return c.Entries[1-i]
+				}
+				entries := append([]*Entry(nil), c.Entries[:i]...)
+				return &collision{c.Hash, append(entries, c.Entries[i+1:]...)}
+			}
+		}
+		return c
+	case *Entry:
+		if c.Hash == hash && c.Key == key {
+			return nil
+		}
+	}
+	return child
+}
+
+// Remove key from beneath node.
+// Returns the replacement for node, as described by dissocChild.
+// Complexity: O(log32(n))
+func (node *Node) dissoc(shift, hash uint32, key Value) Value {
+	bit, idx := slot(node.Bitmap, hash, shift)
+	if node.Bitmap&bit == 0 {
+		return node
+	}
+
+	child := dissocChild(shift+vector.BITS, node.Children[idx], hash, key)
+	if child == node.Children[idx] {
+		return node
+	}
+
+	if child == nil {
+		if len(node.Children) == 1 {
+			return nil
+		}
+		children := make([]Value, 0, len(node.Children)-1)
+		children = append(children, node.Children[:idx]...)
+		children = append(children, node.Children[idx+1:]...)
+
+		if _, isNode := children[0].(*Node); len(children) == 1 && !isNode {
+			return children[0]
+		}
+		return &Node{Bitmap: node.Bitmap &^ bit, Children: children, Size: node.Size - 1}
+	}
+
+	if _, isNode := child.(*Node); len(node.Children) == 1 && !isNode {
+		return child
+	}
+
+	into := &Node{
+		Bitmap:   node.Bitmap,
+		Children: append([]Value(nil), node.Children...),
+		Size:     node.Size - 1,
+	}
+	into.Children[idx] = child
+	return into
+}
+
+// Merge the contents of two slots at shift.
+// Values for keys in both are resolved with fn(key, a, b), or taken from b
+// when fn is nil, in which case identical subtrees are shared untouched.
+// Returns the merged slot and the number of keys found in both.
+// Complexity: O(n + m)
+func mergeChild(shift uint32, a, b Value, fn resolver) (Value, uint32) {
+	if a == b && fn == nil {
+		return a, sizeOf(a)
+	}
+
+	na, aIsNode := a.(*Node)
+	nb, bIsNode := b.(*Node)
+	if aIsNode && bIsNode {
+		return merge(shift, na, nb, fn)
+	}
+
+	if !bIsNode {
+		var dup uint32
+		eachEntry(b, func(e *Entry) bool {
+			var added bool
+			if a, added = assocChild(shift, a, e, fn); !added {
+				dup++
+			}
+			return true
+		})
+		return a, dup
+	}
+
+	var dup uint32
+	eachEntry(a, func(e *Entry) bool {
+		var added bool
+		b, added = assocChild(shift, b, e, func(key, old, new Value) Value {
+			return resolve(fn, key, new, old)
+		})
+		if !added {
+			dup++
+		}
+		return true
+	})
+	return b, dup
+}
+
+// Merge two nodes at shift.
+// Values for keys in both are resolved with fn(key, a, b).
+// Returns the merged node and the number of keys found in both.
+// Complexity: O(n + m)
+func merge(shift uint32, a, b *Node, fn resolver) (*Node, uint32) {
+	var (
+		into = &Node{Bitmap: a.Bitmap | b.Bitmap}
+		dup  uint32
+		ia   int
+		ib   int
+		same = true
+	)
+
+	into.Children = make([]Value, 0, bits.OnesCount32(into.Bitmap))
+	for bitmap := into.Bitmap; bitmap != 0; bitmap &= bitmap - 1 {
+		bit := bitmap & -bitmap
+		inA, inB := a.Bitmap&bit != 0, b.Bitmap&bit != 0
+
+		var child Value
+		switch {
+		case inA && inB:
+			var d uint32
+			child, d = mergeChild(shift+vector.BITS, a.Children[ia], b.Children[ib], fn)
+			dup += d
+			same = same && child == a.Children[ia]
+		case inA:
+			child = a.Children[ia]
+		default:
+			child = b.Children[ib]
+			same = false
+		}
+
+		if inA {
+			ia++
+		}
+		if inB {
+			ib++
+		}
+		into.Children = append(into.Children, child)
+	}
+
+	if same {
+		return a, dup
+	}
+	into.Size = a.Size + b.Size - dup
+	return into, dup
+}
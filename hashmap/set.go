@@ -0,0 +1,77 @@
+package hashmap
+
+// Persistent hash set, stored as a Map whose values are unused.
+type Set struct {
+	// The map holding the elements as keys
+	Map *Map
+}
+
+// Value for the empty set
+var emptySet = &Set{Map: empty}
+
+// Return a new set containing elements...
+// Complexity: O(n)
+func NewSet(elements ...Value) *Set {
+	acc := emptySet
+	for _, v := range elements {
+		acc = acc.Add(v)
+	}
+	return acc
+}
+
+// Return the number of elements in this set.
+// Complexity: O(1)
+func (set *Set) Count() uint32 {
+	return set.Map.Count()
+}
+
+// Return true if value is in the set.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (set *Set) Contains(value Value) bool {
+	return set.Map.Contains(value)
+}
+
+// Add a value to the set.
+// A new set is returned, sharing memory with the original.
+// Adding a value already in the set returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (set *Set) Add(value Value) *Set {
+	if set.Map.Contains(value) {
+		return set
+	}
+	return &Set{Map: set.Map.Assoc(value, nil)}
+}
+
+// Remove a value from the set.
+// A new set is returned, sharing memory with the original.
+// Removing a value not in the set returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (set *Set) Remove(value Value) *Set {
+	m := set.Map.Dissoc(value)
+	if m == set.Map {
+		return set
+	}
+	return &Set{Map: m}
+}
+
+// Return the elements in either this set or other.
+// A new set is returned, sharing memory with both originals.
+// Complexity: O(n + m)
+func (set *Set) Union(other *Set) *Set {
+	m := set.Map.Merge(other.Map, nil)
+	if m == set.Map {
+		return set
+	}
+	return &Set{Map: m}
+}
+
+// Call fn with each element, until fn returns false.
+// Complexity: O(n)
+func (set *Set) Each(fn func(Value) bool) {
+	set.Map.Each(func(k, _ Value) bool {
+		return fn(k)
+	})
+}
@@ -0,0 +1,8 @@
+package heap
+
+// Error type returned when reading from an empty heap
+type EmptyHeap struct{}
+
+func (e *EmptyHeap) Error() string {
+	return "heap is empty"
+}
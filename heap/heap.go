@@ -0,0 +1,112 @@
+package heap
+
+import (
+	"../vector"
+)
+
+// Values storable in the heap
+type Value = interface{}
+
+// Function ordering two values.
+// Returns < 0 if a < b, 0 if a == b, and > 0 if a > b.
+type Comparator func(a, b Value) int
+
+// Persistent priority queue implemented as a leftist heap.
+// The smallest element according to Compare is at the root.
+type Heap struct {
+	// The ordering of elements in the heap
+	Compare Comparator
+	// The root node of the heap
+	Root *Node
+	// The number of elements in the heap
+	Length uint32
+}
+
+// Return a new heap ordered by cmp containing elements...
+// Complexity: O(n)
+func New(cmp Comparator, elements ...Value) *Heap {
+	nodes := make([]*Node, 0, len(elements))
+	for _, v := range elements {
+		nodes = append(nodes, &Node{Value: v, Rank: 1})
+	}
+
+	return &Heap{
+		Compare: cmp,
+		Root:    mergeAll(cmp, nodes),
+		Length:  uint32(len(elements)),
+	}
+}
+
+// Return a new heap ordered by cmp containing the elements of vec.
+// Complexity: O(n)
+func FromVector(cmp Comparator, vec *vector.Vector) *Heap {
+	nodes := make([]*Node, 0, vec.Count())
+	for i := uint32(0); i < vec.Count(); i++ {
+		v, err := vec.Get(i)
+		if err != nil {
+			panic(err)
+		}
+		nodes = append(nodes, &Node{Value: v, Rank: 1})
+	}
+
+	return &Heap{
+		Compare: cmp,
+		Root:    mergeAll(cmp, nodes),
+		Length:  vec.Count(),
+	}
+}
+
+// Return the number of elements in this heap.
+// Complexity: O(1)
+func (h *Heap) Count() uint32 {
+	return h.Length
+}
+
+// Get the smallest element in the heap.
+// Reading from an empty heap is an EmptyHeap error.
+// Complexity: O(1)
+func (h *Heap) Min() (Value, error) {
+	if h.Root == nil {
+		return nil, &EmptyHeap{}
+	}
+	return h.Root.Value, nil
+}
+
+// Insert a value into the heap.
+// A new heap is returned, sharing memory with the original.
+// Complexity: O(log(n))
+func (h *Heap) Insert(value Value) *Heap {
+	return &Heap{
+		Compare: h.Compare,
+		Root:    merge(h.Compare, h.Root, &Node{Value: value, Rank: 1}),
+		Length:  h.Length + 1,
+	}
+}
+
+// Return the heap with the smallest element removed.
+// A new heap is returned, sharing memory with the original.
+// Attempting to delete from an empty heap returns itself.
+// Complexity: O(log(n))
+func (h *Heap) DeleteMin() *Heap {
+	if h.Root == nil {
+		return h
+	}
+
+	return &Heap{
+		Compare: h.Compare,
+		Root:    merge(h.Compare, h.Root.Left, h.Root.Right),
+		Length:  h.Length - 1,
+	}
+}
+
+// Return a heap containing the elements of this heap and other.
+// Both heaps are expected to use the same ordering.
+// A new heap is returned, sharing memory with both originals.
+// Complexity: O(log(n))
+func (h *Heap) Merge(other *Heap) *Heap {
+	return &Heap{
+		Compare: h.Compare,
+		Root:    merge(h.Compare, h.Root, other.Root),
+		Length:  h.Length + other.Length,
+	}
+}
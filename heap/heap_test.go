@@ -0,0 +1,82 @@
+package heap
+
+import (
+	"../vector"
+	"testing"
+)
+
+func CompareInts(a, b Value) int {
+	return a.(int) - b.(int)
+}
+
+func AssertDrains(t *testing.T, h *Heap, elems []Value) {
+	if h.Count() != uint32(len(elems)) {
+		t.Fatalf(`expected h.Count() == %d, got %d`, len(elems), h.Count())
+	}
+
+	for _, v := range elems {
+		x, err := h.Min()
+		if err != nil {
+			t.Fatalf(`expected h.Min() to be ok, got %s`, err)
+		}
+		if x != v {
+			t.Fatalf(`expected h.Min() == %v, got %v`, v, x)
+		}
+		h = h.DeleteMin()
+	}
+
+	if _, err := h.Min(); err == nil {
+		t.Fatalf(`expected h.Min() not to be ok, but was`)
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	h := New(CompareInts)
+
+	if h.Count() != 0 {
+		t.Fatalf(`expected h.Count() == 0, got %d`, h.Count())
+	}
+	if _, err := h.Min(); err == nil {
+		t.Fatalf(`expected h.Min() not to be ok, but was`)
+	}
+	if h.DeleteMin() != h {
+		t.Fatalf(`expected h.DeleteMin() to return itself`)
+	}
+}
+
+func TestNewWithArgs(t *testing.T) {
+	h := New(CompareInts, 5, 3, 9, 1, 7, 3)
+
+	AssertDrains(t, h, []Value{1, 3, 3, 5, 7, 9})
+}
+
+func TestInsert(t *testing.T) {
+	h := New(CompareInts)
+	for _, v := range []int{8, 2, 6, 4, 10, 0} {
+		h = h.Insert(v)
+	}
+	cpy := h.Insert(-1)
+
+	AssertDrains(t, h, []Value{0, 2, 4, 6, 8, 10})
+	AssertDrains(t, cpy, []Value{-1, 0, 2, 4, 6, 8, 10})
+}
+
+func TestMerge(t *testing.T) {
+	a := New(CompareInts, 1, 4, 7)
+	b := New(CompareInts, 2, 3, 8, 0)
+
+	AssertDrains(t, a.Merge(b), []Value{0, 1, 2, 3, 4, 7, 8})
+	AssertDrains(t, a, []Value{1, 4, 7})
+	AssertDrains(t, b, []Value{0, 2, 3, 8})
+}
+
+func TestFromVector(t *testing.T) {
+	vec := vector.New()
+	elems := make([]Value, 0, 500)
+	for i := 0; i < 500; i++ {
+		vec = vec.Append((i * 7919) % 500)
+		elems = append(elems, i)
+	}
+
+	AssertDrains(t, FromVector(CompareInts, vec), elems)
+}
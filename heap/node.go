@@ -0,0 +1,67 @@
+package heap
+
+// Representation of a leftist heap node.
+// The rank of the left child is always >= the rank of the right child, so
+// the right spine is at most O(log(n)) long.
+type Node struct {
+	// The element stored at this node
+	Value Value
+	// The left subtree
+	Left *Node
+	// The right subtree
+	Right *Node
+	// The length of the right spine of this node
+	Rank uint32
+}
+
+// Return the rank of node, where nil has rank 0.
+// Complexity: O(1)
+func (node *Node) rank() uint32 {
+	if node == nil {
+		return 0
+	}
+	return node.Rank
+}
+
+// Create a new node from value and two subtrees, keeping the leftist property.
+// Complexity: O(1)
+func makeNode(value Value, a, b *Node) *Node {
+	if a.rank() < b.rank() {
+		a, b = b, a
+	}
+	return &Node{
+		Value: value,
+		Left:  a,
+		Right: b,
+		Rank:  b.rank() + 1,
+	}
+}
+
+// Merge two heaps, returning a new root that shares memory with both.
+// Only nodes along the right spines are copied.
+// Complexity: O(log(n))
+func merge(cmp Comparator, a, b *Node) *Node {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if cmp(b.Value, a.Value) < 0 {
+		a, b = b, a
+	}
+	return makeNode(a.Value, a.Left, merge(cmp, a.Right, b))
+}
+
+// Build a heap from a queue of nodes by repeatedly merging pairs.
+// Mutates nodes.
+// Complexity: O(n)
+func mergeAll(cmp Comparator, nodes []*Node) *Node {
+	if len(nodes) == 0 {
+		return nil
+	}
+	for len(nodes) > 1 {
+		nodes = append(nodes[2:], merge(cmp, nodes[0], nodes[1]))
+	}
+	return nodes[0]
+}
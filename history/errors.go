@@ -0,0 +1,18 @@
+package history
+
+import (
+	"fmt"
+)
+
+// Error type returned when moving to a version that is not kept
+type NoVersion struct {
+	// The id of the missing version, or 0 if there is none to move to
+	ID uint32
+}
+
+func (e *NoVersion) Error() string {
+	if e.ID == 0 {
+		return "no version to move to"
+	}
+	return fmt.Sprintf("version %d is not in the history", e.ID)
+}
@@ -0,0 +1,258 @@
+package history
+
+import (
+	"../vector"
+	"time"
+	"unsafe"
+)
+
+// The estimated memory used by one vector node, not counting its values
+const NODE_BYTES = uint64(unsafe.Sizeof(vector.Node{})) +
+	vector.SIZE*uint64(unsafe.Sizeof(vector.Value(nil)))
+
+// A recorded version of a vector
+type Version struct {
+	// The id of this version, counting up from 1
+	ID uint32
+	// The id of the version this was recorded from, or 0 for the first
+	Parent uint32
+	// A description of the change
+	Label string
+	// The time the version was recorded
+	Time time.Time
+	// The vector at this version
+	Vector *vector.Vector
+}
+
+// Limits on the versions kept by a history, where 0 means no limit
+type Limits struct {
+	// The maximum number of versions kept
+	MaxVersions int
+	// The maximum memory used by the nodes of every version, counting each
+	// node shared between versions only once
+	MaxBytes uint64
+}
+
+// Undo history of a vector, recording versions as a tree.
+// Recording after an undo starts a new branch, keeping the old one. Once a
+// limit is passed, the oldest versions other than the current one are
+// evicted. A History is not safe for concurrent use.
+type History struct {
+	// The limits on the versions kept
+	limits Limits
+	// The kept versions by id
+	versions map[uint32]*Version
+	// The ids of the kept versions, oldest first
+	order []uint32
+	// The id of the current version
+	current uint32
+	// The id of the last recorded version
+	last uint32
+	// The child each version last moved to, for Redo
+	redo map[uint32]uint32
+	// The number of references to each node from kept versions and nodes
+	refs map[*vector.Node]uint32
+	// The estimated memory used by every referenced node
+	bytes uint64
+}
+
+// Return a new history whose first version is vec.
+// Complexity: O(n) for the nodes of vec
+func New(limits Limits, vec *vector.Vector, label string) *History {
+	h := &History{
+		limits:   limits,
+		versions: make(map[uint32]*Version),
+		redo:     make(map[uint32]uint32),
+		refs:     make(map[*vector.Node]uint32),
+	}
+	h.Record(vec, label)
+	return h
+}
+
+// Return the current version.
+// Complexity: O(1)
+func (h *History) Current() *Version {
+	return h.versions[h.current]
+}
+
+// Return the number of kept versions.
+// Complexity: O(1)
+func (h *History) Count() int {
+	return len(h.order)
+}
+
+// Return the estimated memory used by the nodes of every kept version.
+// Complexity: O(1)
+func (h *History) Bytes() uint64 {
+	return h.bytes
+}
+
+// Return the kept version with id.
+// Access to a version that is not kept is a NoVersion error.
+// Complexity: O(1)
+func (h *History) Get(id uint32) (*Version, error) {
+	if v, ok := h.versions[id]; ok {
+		return v, nil
+	}
+	return nil, &NoVersion{id}
+}
+
+// Return every kept version, oldest first.
+// Complexity: O(n)
+func (h *History) Versions() []*Version {
+	acc := make([]*Version, 0, len(h.order))
+	for _, id := range h.order {
+		acc = append(acc, h.versions[id])
+	}
+	return acc
+}
+
+// Return the kept versions recorded from the version with id, oldest first.
+// More than one child means the history branched there.
+// Complexity: O(n)
+func (h *History) Children(id uint32) []*Version {
+	var acc []*Version
+	for _, cid := range h.order {
+		if h.versions[cid].Parent == id {
+			acc = append(acc, h.versions[cid])
+		}
+	}
+	return acc
+}
+
+// Record vec as a new version following the current one, and make it
+// current, evicting old versions if a limit is passed.
+// Complexity: O(k) for the k nodes of vec not in any kept version
+func (h *History) Record(vec *vector.Vector, label string) *Version {
+	h.last++
+	v := &Version{
+		ID:     h.last,
+		Parent: h.current,
+		Label:  label,
+		Time:   time.Now(),
+		Vector: vec,
+	}
+
+	h.versions[v.ID] = v
+	h.order = append(h.order, v.ID)
+	h.retain(vec.Root)
+
+	if h.current != 0 {
+		h.redo[h.current] = v.ID
+	}
+	h.current = v.ID
+
+	h.evict()
+	return v
+}
+
+// Move to the parent of the current version.
+// Undoing past the first kept version is a NoVersion error.
+// Complexity: O(1)
+func (h *History) Undo() (*Version, error) {
+	parent := h.Current().Parent
+	if _, ok := h.versions[parent]; !ok {
+		return nil, &NoVersion{parent}
+	}
+
+	h.redo[parent] = h.current
+	h.current = parent
+	return h.Current(), nil
+}
+
+// Move to the child of the current version that was last undone or
+// recorded.
+// Redoing with no such child is a NoVersion error.
+// Complexity: O(1)
+func (h *History) Redo() (*Version, error) {
+	child := h.redo[h.current]
+	if _, ok := h.versions[child]; !ok {
+		return nil, &NoVersion{child}
+	}
+
+	h.current = child
+	return h.Current(), nil
+}
+
+// Move to the version with id.
+// Moving to a version that is not kept is a NoVersion error.
+// Complexity: O(1)
+func (h *History) Checkout(id uint32) (*Version, error) {
+	if _, ok := h.versions[id]; !ok {
+		return nil, &NoVersion{id}
+	}
+
+	h.current = id
+	return h.Current(), nil
+}
+
+// Return true if a limit is passed.
+// Complexity: O(1)
+func (h *History) overLimit() bool {
+	return (h.limits.MaxVersions > 0 && len(h.order) > h.limits.MaxVersions) ||
+		(h.limits.MaxBytes > 0 && h.bytes > h.limits.MaxBytes)
+}
+
+// Evict the oldest versions other than the current one until no limit is
+// passed.
+// Complexity: O(n) per eviction, plus the nodes freed
+func (h *History) evict() {
+	for h.overLimit() && len(h.order) > 1 {
+		i := 0
+		if h.order[0] == h.current {
+			i = 1
+		}
+
+		id := h.order[i]
+		h.order = append(h.order[:i], h.order[i+1:]...)
+		h.release(h.versions[id].Vector.Root)
+		delete(h.versions, id)
+		delete(h.redo, id)
+	}
+}
+
+// Return the child nodes of node.
+// Complexity: O(1)
+func children(node *vector.Node) []*vector.Node {
+	if node.Shift == 0 {
+		return nil
+	}
+
+	var acc []*vector.Node
+	for _, e := range node.Elements {
+		if child, ok := e.(*vector.Node); ok {
+			acc = append(acc, child)
+		}
+	}
+	return acc
+}
+
+// Add a reference to node, and to its children if it was not referenced.
+// Complexity: O(k) for the k nodes not already referenced
+func (h *History) retain(node *vector.Node) {
+	h.refs[node]++
+	if h.refs[node] > 1 {
+		return
+	}
+
+	h.bytes += NODE_BYTES
+	for _, child := range children(node) {
+		h.retain(child)
+	}
+}
+
+// Remove a reference to node, and from its children if it is no longer
+// referenced.
+// Complexity: O(k) for the k nodes freed
+func (h *History) release(node *vector.Node) {
+	h.refs[node]--
+	if h.refs[node] > 0 {
+		return
+	}
+
+	delete(h.refs, node)
+	h.bytes -= NODE_BYTES
+	for _, child := range children(node) {
+		h.release(child)
+	}
+}
@@ -0,0 +1,132 @@
+package history
+
+import (
+	"../vector"
+	"testing"
+)
+
+func AssertCurrent(t *testing.T, h *History, id uint32) {
+	if h.Current().ID != id {
+		t.Fatalf(`expected current version %d, got %d`, id, h.Current().ID)
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	vec := vector.New()
+	h := New(Limits{}, vec, "empty")
+	h.Record(vec.Append("a"), "add a")
+	h.Record(vec.Append("a").Append("b"), "add b")
+
+	if v, err := h.Undo(); err != nil || v.Label != "add a" {
+		t.Fatalf(`expected h.Undo() to return "add a", got %v, %s`, v, err)
+	}
+	h.Undo()
+	if _, err := h.Undo(); err == nil {
+		t.Fatalf(`expected undoing the first version not to be ok, but was`)
+	}
+	AssertCurrent(t, h, 1)
+
+	h.Redo()
+	h.Redo()
+	AssertCurrent(t, h, 3)
+	if _, err := h.Redo(); err == nil {
+		t.Fatalf(`expected redoing the last version not to be ok, but was`)
+	}
+}
+
+func TestBranchAfterUndo(t *testing.T) {
+	vec := vector.New("x")
+	h := New(Limits{}, vec, "start")
+	h.Record(vec.Append("a"), "a")
+	h.Undo()
+	h.Record(vec.Append("b"), "b")
+
+	if n := len(h.Children(1)); n != 2 {
+		t.Fatalf(`expected 2 branches from version 1, got %d`, n)
+	}
+
+	h.Undo()
+	if v, _ := h.Redo(); v.Label != "b" {
+		t.Fatalf(`expected redo to follow the newest branch, got %s`, v.Label)
+	}
+
+	if v, err := h.Checkout(2); err != nil || v.Label != "a" {
+		t.Fatalf(`expected h.Checkout(2) to return "a", got %v, %s`, v, err)
+	}
+	if _, err := h.Checkout(9); err == nil {
+		t.Fatalf(`expected h.Checkout(9) not to be ok, but was`)
+	}
+}
+
+func TestEvictByCount(t *testing.T) {
+	vec := vector.New()
+	h := New(Limits{MaxVersions: 3}, vec, "0")
+	for i := 1; i < 10; i++ {
+		vec = vec.Append(i)
+		h.Record(vec, "")
+	}
+
+	if h.Count() != 3 || h.Versions()[0].ID != 8 {
+		t.Fatalf(`expected versions 8-10 to be kept, got %d from %d`, h.Count(), h.Versions()[0].ID)
+	}
+
+	h.Undo()
+	h.Undo()
+	if _, err := h.Undo(); err == nil {
+		t.Fatalf(`expected undoing past the oldest kept version not to be ok, but was`)
+	}
+
+	h.Record(vec.Append("new"), "branch")
+	if h.Count() != 3 || h.Versions()[0].ID != 9 {
+		t.Fatalf(`expected version 8 to be evicted once no longer current`)
+	}
+}
+
+func TestBytesCountSharedNodesOnce(t *testing.T) {
+	vec := vector.New()
+	for i := 0; i < 1000; i++ {
+		vec = vec.Append(i)
+	}
+
+	h := New(Limits{}, vec, "big")
+	full := h.Bytes()
+
+	cpy, _ := vec.Set(500, "x")
+	h.Record(cpy, "edit")
+
+	// only the root and one leaf are unshared
+	if h.Bytes() != full+2*NODE_BYTES {
+		t.Fatalf(`expected %d bytes, got %d`, full+2*NODE_BYTES, h.Bytes())
+	}
+}
+
+func TestEvictByBytes(t *testing.T) {
+	vec := vector.New()
+	for i := 0; i < 1000; i++ {
+		vec = vec.Append(i)
+	}
+
+	h := New(Limits{}, vec, "big")
+	limit := h.Bytes() + 10*NODE_BYTES
+	h = New(Limits{MaxBytes: limit}, vec, "big")
+
+	for i := uint32(0); i < 20; i++ {
+		vec, _ = vec.Set(i*40, "x")
+		h.Record(vec, "edit")
+	}
+
+	if h.Bytes() > limit {
+		t.Fatalf(`expected at most %d bytes, got %d`, limit, h.Bytes())
+	}
+	if h.Count() >= 21 || h.Count() < 2 {
+		t.Fatalf(`expected some versions to be evicted, kept %d`, h.Count())
+	}
+
+	// evicting everything but the current version returns to its own size
+	one := New(Limits{}, h.Current().Vector, "").Bytes()
+	h.limits.MaxVersions = 1
+	h.evict()
+	if h.Bytes() != one {
+		t.Fatalf(`expected %d bytes for one version, got %d`, one, h.Bytes())
+	}
+}
@@ -0,0 +1,14 @@
+package interval
+
+import (
+	"fmt"
+)
+
+// Error type returned when an interval ends before it starts
+type InvalidInterval struct {
+	Interval Interval
+}
+
+func (e *InvalidInterval) Error() string {
+	return fmt.Sprintf("interval [%v, %v] ends before it starts", e.Interval.Start, e.Interval.End)
+}
@@ -0,0 +1,149 @@
+package interval
+
+// Values storable in the tree
+type Value = interface{}
+
+// Function ordering two end points.
+// Returns < 0 if a < b, 0 if a == b, and > 0 if a > b.
+type Comparator func(a, b Value) int
+
+// A closed interval [Start, End]
+type Interval struct {
+	// The first point in the interval
+	Start Value
+	// The last point in the interval
+	End Value
+}
+
+// Persistent interval tree mapping intervals to values.
+// Intervals are kept in a balanced tree ordered by start point, with each
+// node recording the greatest end point beneath it.
+type Tree struct {
+	// The ordering of end points
+	Compare Comparator
+	// The root node of the tree, nil when empty
+	Root *Node
+	// The number of intervals in the tree
+	Length uint32
+}
+
+// Return a new empty tree ordering end points by cmp.
+// Complexity: O(1)
+func New(cmp Comparator) *Tree {
+	return &Tree{Compare: cmp}
+}
+
+// Return the number of intervals in this tree.
+// Complexity: O(1)
+func (tree *Tree) Count() uint32 {
+	return tree.Length
+}
+
+// Insert the interval [start, end] with value, replacing the value of an
+// equal interval.
+// A new tree is returned, sharing memory with the original.
+// Intervals ending before they start are an InvalidInterval error.
+// Complexity: O(log(n))
+func (tree *Tree) Insert(start, end Value, value Value) (*Tree, error) {
+	iv := Interval{start, end}
+	if tree.Compare(start, end) > 0 {
+		return nil, &InvalidInterval{iv}
+	}
+
+	root, added := insert(tree.Compare, tree.Root, iv, value)
+
+	length := tree.Length
+	if added {
+		length++
+	}
+
+	return &Tree{Compare: tree.Compare, Root: root, Length: length}, nil
+}
+
+// Remove the interval [start, end].
+// A new tree is returned, sharing memory with the original.
+// Deleting an interval that is not in the tree returns itself.
+// Complexity: O(log(n))
+func (tree *Tree) Delete(start, end Value) *Tree {
+	root := remove(tree.Compare, tree.Root, Interval{start, end})
+	if root == tree.Root {
+		return tree
+	}
+
+	return &Tree{Compare: tree.Compare, Root: root, Length: tree.Length - 1}
+}
+
+// Return an iterator over the intervals overlapping [from, to], ordered by
+// start point.
+// Results are found lazily as the iterator advances.
+// Complexity: O(log(n)) per result
+func (tree *Tree) Overlapping(from, to Value) *Iterator {
+	it := &Iterator{
+		compare: tree.Compare,
+		from:    from,
+		to:      to,
+	}
+	it.pushLeft(tree.Root)
+	return it
+}
+
+// Return an iterator over the intervals containing point.
+// Complexity: O(log(n)) per result
+func (tree *Tree) Stabbing(point Value) *Iterator {
+	return tree.Overlapping(point, point)
+}
+
+// Iterator over the results of an overlap query.
+type Iterator struct {
+	// The ordering of end points
+	compare Comparator
+	// The first point in the query
+	from Value
+	// The last point in the query
+	to Value
+	// Nodes still to be visited, with the next node last
+	stack []*Node
+	// The current result
+	current *Node
+}
+
+// Push node and its left spine, skipping subtrees ending before the query.
+func (it *Iterator) pushLeft(node *Node) {
+	for node != nil && it.compare(node.MaxEnd, it.from) >= 0 {
+		it.stack = append(it.stack, node)
+		node = node.Left
+	}
+}
+
+// Advance to the next overlapping interval.
+// Returns false when there are no more results.
+func (it *Iterator) Next() bool {
+	for len(it.stack) > 0 {
+		node := it.stack[len(it.stack)-1]
+		it.stack = it.stack[:len(it.stack)-1]
+
+		if it.compare(node.Interval.Start, it.to) > 0 {
+			// every remaining interval starts after the query
+			break
+		}
+
+		it.pushLeft(node.Right)
+		if it.compare(node.Interval.End, it.from) >= 0 {
+			it.current = node
+			return true
+		}
+	}
+
+	it.stack, it.current = nil, nil
+	return false
+}
+
+// Return the current interval.
+func (it *Iterator) Interval() Interval {
+	return it.current.Interval
+}
+
+// Return the value of the current interval.
+func (it *Iterator) Value() Value {
+	return it.current.Value
+}
@@ -0,0 +1,110 @@
+package interval
+
+import (
+	"testing"
+)
+
+func CompareInts(a, b Value) int {
+	return a.(int) - b.(int)
+}
+
+func AssertResults(t *testing.T, it *Iterator, values []Value) {
+	var found []Value
+	for it.Next() {
+		found = append(found, it.Value())
+	}
+
+	if len(found) != len(values) {
+		t.Fatalf(`expected results %v, got %v`, values, found)
+	}
+	for i, v := range values {
+		if found[i] != v {
+			t.Fatalf(`expected results %v, got %v`, values, found)
+		}
+	}
+}
+
+func Bookings(t *testing.T) *Tree {
+	tree := New(CompareInts)
+	for _, b := range [][3]Value{
+		{1, 3, "a"},
+		{2, 8, "b"},
+		{5, 6, "c"},
+		{7, 10, "d"},
+		{12, 15, "e"},
+		{14, 14, "f"},
+	} {
+		var err error
+		tree, err = tree.Insert(b[0], b[1], b[2])
+		if err != nil {
+			t.Fatalf(`expected tree.Insert(%v, %v, ...) to be ok, got %s`, b[0], b[1], err)
+		}
+	}
+	return tree
+}
+
+func TestInsert(t *testing.T) {
+	tree := Bookings(t)
+
+	if tree.Count() != 6 {
+		t.Fatalf(`expected tree.Count() == 6, got %d`, tree.Count())
+	}
+
+	cpy, _ := tree.Insert(5, 6, "C")
+	if cpy.Count() != 6 {
+		t.Fatalf(`expected replacing an interval to keep cpy.Count() == 6, got %d`, cpy.Count())
+	}
+	AssertResults(t, cpy.Stabbing(5), []Value{"b", "C"})
+	AssertResults(t, tree.Stabbing(5), []Value{"b", "c"})
+
+	if _, err := tree.Insert(4, 3, "x"); err == nil {
+		t.Fatalf(`expected tree.Insert(4, 3, ...) not to be ok, but was`)
+	}
+}
+
+func TestOverlapping(t *testing.T) {
+	tree := Bookings(t)
+
+	AssertResults(t, tree.Overlapping(4, 7), []Value{"b", "c", "d"})
+	AssertResults(t, tree.Overlapping(0, 1), []Value{"a"})
+	AssertResults(t, tree.Overlapping(11, 11), nil)
+	AssertResults(t, tree.Overlapping(10, 14), []Value{"d", "e", "f"})
+	AssertResults(t, tree.Overlapping(0, 100), []Value{"a", "b", "c", "d", "e", "f"})
+}
+
+func TestStabbing(t *testing.T) {
+	tree := Bookings(t)
+
+	AssertResults(t, tree.Stabbing(3), []Value{"a", "b"})
+	AssertResults(t, tree.Stabbing(14), []Value{"e", "f"})
+	AssertResults(t, tree.Stabbing(16), nil)
+}
+
+func TestDelete(t *testing.T) {
+	tree := Bookings(t)
+	cpy := tree.Delete(2, 8).Delete(14, 14)
+
+	AssertResults(t, cpy.Overlapping(0, 100), []Value{"a", "c", "d", "e"})
+	AssertResults(t, tree.Overlapping(0, 100), []Value{"a", "b", "c", "d", "e", "f"})
+
+	if cpy.Count() != 4 {
+		t.Fatalf(`expected cpy.Count() == 4, got %d`, cpy.Count())
+	}
+	if cpy.Delete(2, 8) != cpy {
+		t.Fatalf(`expected deleting a missing interval to return itself`)
+	}
+}
+
+func TestManyIntervals(t *testing.T) {
+	tree := New(CompareInts)
+	for i := 0; i < 1000; i++ {
+		tree, _ = tree.Insert(i, i+5, i)
+	}
+
+	AssertResults(t, tree.Stabbing(500), []Value{495, 496, 497, 498, 499, 500})
+
+	for i := 0; i < 1000; i += 2 {
+		tree = tree.Delete(i, i+5)
+	}
+	AssertResults(t, tree.Stabbing(500), []Value{495, 497, 499})
+}
@@ -0,0 +1,147 @@
+package interval
+
+// Representation of an AVL tree node ordered by interval start, then end.
+// Each node is annotated with the greatest end point beneath it, so subtrees
+// ending before a query can be skipped.
+type Node struct {
+	// The interval stored at this node
+	Interval Interval
+	// The value stored for the interval
+	Value Value
+	// Intervals ordered before this one
+	Left *Node
+	// Intervals ordered after this one
+	Right *Node
+	// The height of this subtree
+	Height uint32
+	// The greatest end point in this subtree
+	MaxEnd Value
+}
+
+// Return the height of node, where nil has height 0.
+// Complexity: O(1)
+func (node *Node) height() uint32 {
+	if node == nil {
+		return 0
+	}
+	return node.Height
+}
+
+// Create a new node, computing its height and max end point.
+// Complexity: O(1)
+func newNode(cmp Comparator, iv Interval, value Value, left, right *Node) *Node {
+	node := &Node{
+		Interval: iv,
+		Value:    value,
+		Left:     left,
+		Right:    right,
+		Height:   left.height() + 1,
+		MaxEnd:   iv.End,
+	}
+	if right.height() >= node.Height {
+		node.Height = right.height() + 1
+	}
+	if left != nil && cmp(left.MaxEnd, node.MaxEnd) > 0 {
+		node.MaxEnd = left.MaxEnd
+	}
+	if right != nil && cmp(right.MaxEnd, node.MaxEnd) > 0 {
+		node.MaxEnd = right.MaxEnd
+	}
+	return node
+}
+
+// Create a new node, rotating if the subtrees differ in height by 2.
+// Complexity: O(1)
+func balance(cmp Comparator, iv Interval, value Value, left, right *Node) *Node {
+	switch {
+	case left.height() > right.height()+1:
+		if left.Left.height() >= left.Right.height() {
+			return newNode(cmp, left.Interval, left.Value,
+				left.Left,
+				newNode(cmp, iv, value, left.Right, right))
+		}
+		return newNode(cmp, left.Right.Interval, left.Right.Value,
+			newNode(cmp, left.Interval, left.Value, left.Left, left.Right.Left),
+			newNode(cmp, iv, value, left.Right.Right, right))
+	case right.height() > left.height()+1:
+		if right.Right.height() >= right.Left.height() {
+			return newNode(cmp, right.Interval, right.Value,
+				newNode(cmp, iv, value, left, right.Left),
+				right.Right)
+		}
+		return newNode(cmp, right.Left.Interval, right.Left.Value,
+			newNode(cmp, iv, value, left, right.Left.Left),
+			newNode(cmp, right.Interval, right.Value, right.Left.Right, right.Right))
+	}
+	return newNode(cmp, iv, value, left, right)
+}
+
+// Order two intervals by start, then by end.
+// Complexity: O(1)
+func compareIntervals(cmp Comparator, a, b Interval) int {
+	if c := cmp(a.Start, b.Start); c != 0 {
+		return c
+	}
+	return cmp(a.End, b.End)
+}
+
+// Insert iv beneath node, replacing the value of an equal interval.
+// Returns true if the interval was not already present.
+// Complexity: O(log(n))
+func insert(cmp Comparator, node *Node, iv Interval, value Value) (*Node, bool) {
+	if node == nil {
+		return newNode(cmp, iv, value, nil, nil), true
+	}
+
+	c := compareIntervals(cmp, iv, node.Interval)
+	switch {
+	case c < 0:
+		left, added := insert(cmp, node.Left, iv, value)
+		return balance(cmp, node.Interval, node.Value, left, node.Right), added
+	case c > 0:
+		right, added := insert(cmp, node.Right, iv, value)
+		return balance(cmp, node.Interval, node.Value, node.Left, right), added
+	}
+	return newNode(cmp, iv, value, node.Left, node.Right), false
+}
+
+// Remove iv from beneath node, returning the new root.
+// Returns node itself if iv is not present.
+// Complexity: O(log(n))
+func remove(cmp Comparator, node *Node, iv Interval) *Node {
+	if node == nil {
+		return nil
+	}
+
+	c := compareIntervals(cmp, iv, node.Interval)
+	switch {
+	case c < 0:
+		left := remove(cmp, node.Left, iv)
+		if left == node.Left {
+			return node
+		}
+		return balance(cmp, node.Interval, node.Value, left, node.Right)
+	case c > 0:
+		right := remove(cmp, node.Right, iv)
+		if right == node.Right {
+			return node
+		}
+		return balance(cmp, node.Interval, node.Value, node.Left, right)
+	}
+
+	if node.Right == nil {
+		return node.Left
+	}
+	min, right := removeMin(cmp, node.Right)
+	return balance(cmp, min.Interval, min.Value, node.Left, right)
+}
+
+// Remove the first node beneath node, returning it and the new root.
+// Complexity: O(log(n))
+func removeMin(cmp Comparator, node *Node) (*Node, *Node) {
+	if node.Left == nil {
+		return node, node.Right
+	}
+	min, left := removeMin(cmp, node.Left)
+	return min, balance(cmp, node.Interval, node.Value, left, node.Right)
+}
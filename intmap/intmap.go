@@ -0,0 +1,86 @@
+package intmap
+
+// Values storable in the map
+type Value = interface{}
+
+// Persistent map from uint64 keys to values.
+// This implements the big-endian Patricia trie described by Okasaki and Gill,
+// so keys are visited in ascending order and merges skip disjoint subtrees.
+type IntMap struct {
+	// The root node of the trie, nil when empty
+	Root *Node
+}
+
+// Value for the empty map
+var empty = &IntMap{}
+
+// Return a new empty map.
+// Complexity: O(1)
+func New() *IntMap {
+	return empty
+}
+
+// Return the number of keys in this map.
+// Complexity: O(1)
+func (m *IntMap) Count() uint32 {
+	return m.Root.size()
+}
+
+// Get the value stored for key.
+// Returns false if key is not in the map.
+// Complexity: O(min(n, 64))
+func (m *IntMap) Lookup(key uint64) (Value, bool) {
+	leaf, ok := lookup(m.Root, key)
+	if !ok {
+		return nil, false
+	}
+	return leaf.Value, true
+}
+
+// Insert key into the map with value, replacing any existing value.
+// A new map is returned, sharing memory with the original.
+// Complexity: O(min(n, 64))
+func (m *IntMap) Insert(key uint64, value Value) *IntMap {
+	return &IntMap{Root: insert(m.Root, key, value, nil)}
+}
+
+// Associate key with value, as Insert.
+// Complexity: O(min(n, 64))
+func (m *IntMap) Assoc(key uint64, value Value) *IntMap {
+	return m.Insert(key, value)
+}
+
+// Remove key from the map.
+// A new map is returned, sharing memory with the original.
+// Deleting a key that is not in the map returns itself.
+// Complexity: O(min(n, 64))
+func (m *IntMap) Delete(key uint64) *IntMap {
+	root := remove(m.Root, key)
+	if root == m.Root {
+		return m
+	}
+	return &IntMap{Root: root}
+}
+
+// Return the keys of this map and other.
+// Values of keys in both maps are combined with fn(key, mine, theirs).
+// A nil fn keeps the values from this map.
+// A new map is returned, sharing disjoint subtrees with both originals.
+// Complexity: O(n + m) worst case, proportional to the overlap
+func (m *IntMap) UnionWith(other *IntMap, fn Combiner) *IntMap {
+	return &IntMap{Root: union(m.Root, other.Root, fn)}
+}
+
+// Return the keys present in both this map and other.
+// Values are combined with fn(key, mine, theirs).
+// Complexity: O(n + m) worst case, proportional to the overlap
+func (m *IntMap) IntersectionWith(other *IntMap, fn Combiner) *IntMap {
+	return &IntMap{Root: intersection(m.Root, other.Root, fn)}
+}
+
+// Call fn with each key and value in ascending key order, until fn returns
+// false.
+// Complexity: O(n)
+func (m *IntMap) Each(fn func(key uint64, value Value) bool) {
+	each(m.Root, fn)
+}
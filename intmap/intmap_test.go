@@ -0,0 +1,128 @@
+package intmap
+
+import (
+	"testing"
+)
+
+func AssertEntries(t *testing.T, m *IntMap, keys []uint64, values []Value) {
+	var (
+		foundKeys   []uint64
+		foundValues []Value
+	)
+	m.Each(func(k uint64, v Value) bool {
+		foundKeys = append(foundKeys, k)
+		foundValues = append(foundValues, v)
+		return true
+	})
+
+	if len(foundKeys) != len(keys) {
+		t.Fatalf(`expected keys %v, got %v`, keys, foundKeys)
+	}
+	for i, k := range keys {
+		if foundKeys[i] != k || foundValues[i] != values[i] {
+			t.Fatalf(`expected %v => %v, got %v => %v`, keys, values, foundKeys, foundValues)
+		}
+		if v, ok := m.Lookup(k); !ok || v != values[i] {
+			t.Fatalf(`expected m.Lookup(%d) == %v, got %v`, k, values[i], v)
+		}
+	}
+
+	if m.Count() != uint32(len(keys)) {
+		t.Fatalf(`expected m.Count() == %d, got %d`, len(keys), m.Count())
+	}
+}
+
+func Sum(key uint64, a, b Value) Value {
+	return a.(int) + b.(int)
+}
+
+func TestInsertAndLookup(t *testing.T) {
+	m := New().
+		Insert(1<<63, "top").
+		Insert(42, "a").
+		Insert(7, "b").
+		Insert(0, "zero").
+		Insert(1<<40, "c")
+
+	AssertEntries(
+		t, m,
+		[]uint64{0, 7, 42, 1 << 40, 1 << 63},
+		[]Value{"zero", "b", "a", "c", "top"},
+	)
+
+	if _, ok := m.Lookup(8); ok {
+		t.Fatalf(`expected m.Lookup(8) not to be ok, but was`)
+	}
+
+	cpy := m.Insert(42, "A")
+	AssertEntries(t, cpy, []uint64{0, 7, 42, 1 << 40, 1 << 63}, []Value{"zero", "b", "A", "c", "top"})
+	AssertEntries(t, m, []uint64{0, 7, 42, 1 << 40, 1 << 63}, []Value{"zero", "b", "a", "c", "top"})
+}
+
+func TestDelete(t *testing.T) {
+	m := New()
+	for i := uint64(0); i < 100; i++ {
+		m = m.Insert(i*i, int(i))
+	}
+
+	cpy := m
+	for i := uint64(0); i < 100; i += 2 {
+		cpy = cpy.Delete(i * i)
+	}
+
+	if cpy.Count() != 50 || m.Count() != 100 {
+		t.Fatalf(`expected counts 50 and 100, got %d and %d`, cpy.Count(), m.Count())
+	}
+	if _, ok := cpy.Lookup(16); ok {
+		t.Fatalf(`expected cpy.Lookup(16) not to be ok, but was`)
+	}
+	if v, ok := cpy.Lookup(25); !ok || v != 5 {
+		t.Fatalf(`expected cpy.Lookup(25) == 5, got %v`, v)
+	}
+	if cpy.Delete(16) != cpy {
+		t.Fatalf(`expected deleting a missing key to return itself`)
+	}
+}
+
+func TestUnionWith(t *testing.T) {
+	a := New().Insert(1, 10).Insert(2, 20).Insert(1<<50, 30)
+	b := New().Insert(2, 2).Insert(3, 3).Insert(1<<60, 4)
+
+	AssertEntries(
+		t, a.UnionWith(b, Sum),
+		[]uint64{1, 2, 3, 1 << 50, 1 << 60},
+		[]Value{10, 22, 3, 30, 4},
+	)
+	AssertEntries(t, a, []uint64{1, 2, 1 << 50}, []Value{10, 20, 30})
+}
+
+func TestUnionWithNilKeepsMine(t *testing.T) {
+	a := New().Insert(1, 10).Insert(2, 20)
+	b := New().Insert(2, 2).Insert(3, 3)
+
+	AssertEntries(t, a.UnionWith(b, nil), []uint64{1, 2, 3}, []Value{10, 20, 3})
+	AssertEntries(t, New().Insert(2, 20).UnionWith(b, nil), []uint64{2, 3}, []Value{20, 3})
+	AssertEntries(t, a.UnionWith(New().Insert(2, 2), nil), []uint64{1, 2}, []Value{10, 20})
+}
+
+func TestUnionWithSharesDisjointSubtrees(t *testing.T) {
+	low := New().Insert(1, 1).Insert(2, 2).Insert(3, 3)
+	high := New().Insert(1<<62, 4).Insert(1<<62+1, 5)
+
+	u := low.UnionWith(high, Sum)
+	if u.Root.Left != low.Root || u.Root.Right != high.Root {
+		t.Fatalf(`expected disjoint subtrees to be shared`)
+	}
+}
+
+func TestIntersectionWith(t *testing.T) {
+	a := New().Insert(1, 10).Insert(2, 20).Insert(1<<50, 30).Insert(9, 90)
+	b := New().Insert(2, 2).Insert(3, 3).Insert(1<<50, 4)
+
+	AssertEntries(
+		t, a.IntersectionWith(b, Sum),
+		[]uint64{2, 1 << 50},
+		[]Value{22, 34},
+	)
+	AssertEntries(t, a.IntersectionWith(New(), Sum), nil, nil)
+}
@@ -0,0 +1,262 @@
+package intmap
+
+import (
+	"math/bits"
+)
+
+// Representation of a big-endian Patricia trie node.
+// Leaves hold a single key and value. Branches hold the common prefix of
+// every key beneath them and the highest bit at which those keys differ.
+// Keys with that bit clear are on the left, keys with it set on the right.
+type Node struct {
+	// The key of a leaf, or the common prefix of a branch
+	Prefix uint64
+	// The branching bit of a branch, 0 for a leaf
+	Mask uint64
+	// The value of a leaf
+	Value Value
+	// Keys with the branching bit clear
+	Left *Node
+	// Keys with the branching bit set
+	Right *Node
+	// The number of keys beneath this node
+	Size uint32
+}
+
+// Function combining the values of a key present in both maps
+type Combiner func(key uint64, a, b Value) Value
+
+// Create a new leaf.
+// Complexity: O(1)
+func newLeaf(key uint64, value Value) *Node {
+	return &Node{Prefix: key, Value: value, Size: 1}
+}
+
+// Create a new branch.
+// Complexity: O(1)
+func newBranch(prefix, mask uint64, left, right *Node) *Node {
+	return &Node{
+		Prefix: prefix,
+		Mask:   mask,
+		Left:   left,
+		Right:  right,
+		Size:   left.Size + right.Size,
+	}
+}
+
+// Create a new branch, collapsing it if either side is empty.
+// Complexity: O(1)
+func branch(prefix, mask uint64, left, right *Node) *Node {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	}
+	return newBranch(prefix, mask, left, right)
+}
+
+// Return true if node is a leaf.
+// Complexity: O(1)
+func (node *Node) isLeaf() bool {
+	return node.Mask == 0
+}
+
+// Return the number of keys beneath node, where nil has none.
+// Complexity: O(1)
+func (node *Node) size() uint32 {
+	if node == nil {
+		return 0
+	}
+	return node.Size
+}
+
+// Return true if the branching bit m is clear in key.
+// Complexity: O(1)
+func zero(key, m uint64) bool {
+	return key&m == 0
+}
+
+// Return the bits of key above the branching bit m.
+// Complexity: O(1)
+func mask(key, m uint64) uint64 {
+	return key &^ (m | (m - 1))
+}
+
+// Return true if key does not share the prefix of a branch.
+// Complexity: O(1)
+func nomatch(key, prefix, m uint64) bool {
+	return mask(key, m) != prefix
+}
+
+// Return the highest bit at which a and b differ.
+// Complexity: O(1)
+func branchingBit(a, b uint64) uint64 {
+	return 1 << uint(63-bits.LeadingZeros64(a^b))
+}
+
+// Join two trees whose prefixes differ.
+// Complexity: O(1)
+func join(p1 uint64, t1 *Node, p2 uint64, t2 *Node) *Node {
+	m := branchingBit(p1, p2)
+	if zero(p1, m) {
+		return newBranch(mask(p1, m), m, t1, t2)
+	}
+	return newBranch(mask(p1, m), m, t2, t1)
+}
+
+// Find the leaf for key beneath node.
+// Complexity: O(min(n, 64))
+func lookup(node *Node, key uint64) (*Node, bool) {
+	for node != nil {
+		switch {
+		case node.isLeaf():
+			return node, node.Prefix == key
+		case nomatch(key, node.Prefix, node.Mask):
+			return nil, false
+		case zero(key, node.Mask):
+			node = node.Left
+		default:
+			node = node.Right
+		}
+	}
+	return nil, false
+}
+
+// Insert key beneath node, combining with any existing value using fn.
+// Existing values are replaced when fn is nil.
+// Complexity: O(min(n, 64))
+func insert(node *Node, key uint64, value Value, fn Combiner) *Node {
+	switch {
+	case node == nil:
+		return newLeaf(key, value)
+	case node.isLeaf():
+		if node.Prefix == key {
+			if fn != nil {
+				value = fn(key, value, node.Value)
+			}
+			return newLeaf(key, value)
+		}
+		return join(key, newLeaf(key, value), node.Prefix, node)
+	case nomatch(key, node.Prefix, node.Mask):
+		return join(key, newLeaf(key, value), node.Prefix, node)
+	case zero(key, node.Mask):
+		return newBranch(node.Prefix, node.Mask, insert(node.Left, key, value, fn), node.Right)
+	}
+	return newBranch(node.Prefix, node.Mask, node.Left, insert(node.Right, key, value, fn))
+}
+
+// Remove key from beneath node, returning the new node, which may be nil.
+// Returns node itself if key is not present.
+// Complexity: O(min(n, 64))
+func remove(node *Node, key uint64) *Node {
+	switch {
+	case node == nil:
+		return nil
+	case node.isLeaf():
+		if node.Prefix == key {
+			return nil
+		}
+		return node
+	case nomatch(key, node.Prefix, node.Mask):
+		return node
+	case zero(key, node.Mask):
+		left := remove(node.Left, key)
+		if left == node.Left {
+			return node
+		}
+		return branch(node.Prefix, node.Mask, left, node.Right)
+	}
+
+	right := remove(node.Right, key)
+	if right == node.Right {
+		return node
+	}
+	return branch(node.Prefix, node.Mask, node.Left, right)
+}
+
+// Return the union of s and t, combining values for shared keys with fn.
+// Values from s are kept when fn is nil.
+// Subtrees whose keys appear in only one side are shared untouched.
+// Complexity: O(n + m) worst case, proportional to the overlap
+func union(s, t *Node, fn Combiner) *Node {
+	switch {
+	case s == nil:
+		return t
+	case t == nil:
+		return s
+	case s.isLeaf():
+		return insert(t, s.Prefix, s.Value, fn)
+	case t.isLeaf():
+		return insert(s, t.Prefix, t.Value, func(key uint64, b, a Value) Value {
+			if fn == nil {
+				return a
+			}
+			return fn(key, a, b)
+		})
+	case s.Mask == t.Mask && s.Prefix == t.Prefix:
+		return newBranch(s.Prefix, s.Mask, union(s.Left, t.Left, fn), union(s.Right, t.Right, fn))
+	case s.Mask > t.Mask && !nomatch(t.Prefix, s.Prefix, s.Mask):
+		if zero(t.Prefix, s.Mask) {
+			return newBranch(s.Prefix, s.Mask, union(s.Left, t, fn), s.Right)
+		}
+		return newBranch(s.Prefix, s.Mask, s.Left, union(s.Right, t, fn))
+	case s.Mask < t.Mask && !nomatch(s.Prefix, t.Prefix, t.Mask):
+		if zero(s.Prefix, t.Mask) {
+			return newBranch(t.Prefix, t.Mask, union(s, t.Left, fn), t.Right)
+		}
+		return newBranch(t.Prefix, t.Mask, t.Left, union(s, t.Right, fn))
+	}
+	return join(s.Prefix, s, t.Prefix, t)
+}
+
+// Return the keys present in both s and t, combining their values with fn.
+// Subtrees whose prefixes are disjoint are skipped without being visited.
+// Complexity: O(n + m) worst case, proportional to the overlap
+func intersection(s, t *Node, fn Combiner) *Node {
+	switch {
+	case s == nil || t == nil:
+		return nil
+	case s.isLeaf():
+		if leaf, ok := lookup(t, s.Prefix); ok {
+			return newLeaf(s.Prefix, fn(s.Prefix, s.Value, leaf.Value))
+		}
+		return nil
+	case t.isLeaf():
+		if leaf, ok := lookup(s, t.Prefix); ok {
+			return newLeaf(t.Prefix, fn(t.Prefix, leaf.Value, t.Value))
+		}
+		return nil
+	case s.Mask == t.Mask && s.Prefix == t.Prefix:
+		return branch(
+			s.Prefix,
+			s.Mask,
+			intersection(s.Left, t.Left, fn),
+			intersection(s.Right, t.Right, fn),
+		)
+	case s.Mask > t.Mask && !nomatch(t.Prefix, s.Prefix, s.Mask):
+		if zero(t.Prefix, s.Mask) {
+			return intersection(s.Left, t, fn)
+		}
+		return intersection(s.Right, t, fn)
+	case s.Mask < t.Mask && !nomatch(s.Prefix, t.Prefix, t.Mask):
+		if zero(s.Prefix, t.Mask) {
+			return intersection(s, t.Left, fn)
+		}
+		return intersection(s, t.Right, fn)
+	}
+	return nil
+}
+
+// Visit each key and value beneath node in ascending key order, until fn
+// returns false.
+// Complexity: O(n)
+func each(node *Node, fn func(uint64, Value) bool) bool {
+	switch {
+	case node == nil:
+		return true
+	case node.isLeaf():
+		return fn(node.Prefix, node.Value)
+	}
+	return each(node.Left, fn) && each(node.Right, fn)
+}
@@ -0,0 +1,15 @@
+package lens
+
+import (
+	"fmt"
+)
+
+// Error type returned when a focused value does not have the lens's type
+type WrongType struct {
+	// The value found
+	Value interface{}
+}
+
+func (e *WrongType) Error() string {
+	return fmt.Sprintf("value %v (%T) has the wrong type for this lens", e.Value, e.Value)
+}
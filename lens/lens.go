@@ -0,0 +1,108 @@
+package lens
+
+import (
+	"../vector"
+)
+
+// Focus on a part A of a whole S.
+// Setting through a lens returns a new S, leaving the original unchanged, so
+// lenses over persistent collections and value-typed structs compose into
+// deep immutable updates.
+type Lens[S, A any] struct {
+	// Read the focus from a whole
+	Getter func(S) (A, error)
+	// Return a whole with the focus replaced
+	Setter func(S, A) (S, error)
+}
+
+// Return a new lens from functions that cannot fail.
+// Complexity: O(1)
+func New[S, A any](get func(S) A, set func(S, A) S) Lens[S, A] {
+	return Lens[S, A]{
+		Getter: func(s S) (A, error) {
+			return get(s), nil
+		},
+		Setter: func(s S, a A) (S, error) {
+			return set(s, a), nil
+		},
+	}
+}
+
+// Get the focus of this lens in s.
+func (l Lens[S, A]) Get(s S) (A, error) {
+	return l.Getter(s)
+}
+
+// Return s with the focus of this lens replaced by a.
+func (l Lens[S, A]) Set(s S, a A) (S, error) {
+	return l.Setter(s, a)
+}
+
+// Return s with the focus of this lens replaced by the result of fn.
+func (l Lens[S, A]) Modify(s S, fn func(A) A) (S, error) {
+	a, err := l.Getter(s)
+	if err != nil {
+		var zero S
+		return zero, err
+	}
+	return l.Setter(s, fn(a))
+}
+
+// Return a lens focusing through outer, then inner.
+// Setting where outer is out of bounds, such as at the append position of an
+// Index lens, sets inner on the zero A and leaves outer to accept or reject it.
+// Complexity: O(1)
+func Compose[S, A, B any](outer Lens[S, A], inner Lens[A, B]) Lens[S, B] {
+	return Lens[S, B]{
+		Getter: func(s S) (B, error) {
+			a, err := outer.Getter(s)
+			if err != nil {
+				var zero B
+				return zero, err
+			}
+			return inner.Getter(a)
+		},
+		Setter: func(s S, b B) (S, error) {
+			a, err := outer.Getter(s)
+			if _, ok := err.(*vector.OutOfBounds); ok {
+				var zero A
+				a, err = zero, nil
+			}
+			if err != nil {
+				var zero S
+				return zero, err
+			}
+			a, err = inner.Setter(a, b)
+			if err != nil {
+				var zero S
+				return zero, err
+			}
+			return outer.Setter(s, a)
+		},
+	}
+}
+
+// Return a lens focusing on the element at key in a vector of As.
+// Access to a key that is not in the vector is a vector.OutOfBounds error,
+// and an element that is not an A is a WrongType error. Setting the append
+// position appends.
+// Complexity: O(1)
+func Index[A any](key uint32) Lens[*vector.Vector, A] {
+	return Lens[*vector.Vector, A]{
+		Getter: func(vec *vector.Vector) (A, error) {
+			var zero A
+			v, err := vec.Get(key)
+			if err != nil || v == nil {
+				return zero, err
+			}
+			a, ok := v.(A)
+			if !ok {
+				return zero, &WrongType{v}
+			}
+			return a, nil
+		},
+		Setter: func(vec *vector.Vector, a A) (*vector.Vector, error) {
+			return vec.Set(key, a)
+		},
+	}
+}
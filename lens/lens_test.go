@@ -0,0 +1,112 @@
+package lens
+
+import (
+	"../vector"
+	"testing"
+)
+
+type Address struct {
+	City string
+}
+
+type User struct {
+	Name      string
+	Addresses *vector.Vector
+}
+
+var addresses = New(
+	func(u User) *vector.Vector { return u.Addresses },
+	func(u User, v *vector.Vector) User { u.Addresses = v; return u },
+)
+
+var city = New(
+	func(a Address) string { return a.City },
+	func(a Address, c string) Address { a.City = c; return a },
+)
+
+func Alice() User {
+	return User{"alice", vector.New(Address{"Paris"}, Address{"Oslo"})}
+}
+
+func TestGetAndSet(t *testing.T) {
+	u := Alice()
+
+	addrs, _ := addresses.Get(u)
+	if addrs.Count() != 2 {
+		t.Fatalf(`expected 2 addresses, got %d`, addrs.Count())
+	}
+
+	moved, err := addresses.Set(u, vector.New())
+	if err != nil {
+		t.Fatalf(`expected addresses.Set() to be ok, got %s`, err)
+	}
+	if moved.Addresses.Count() != 0 || u.Addresses.Count() != 2 {
+		t.Fatalf(`expected only moved to have no addresses`)
+	}
+}
+
+func TestCompose(t *testing.T) {
+	u := Alice()
+	second := Compose(Compose(addresses, Index[Address](1)), city)
+
+	if c, err := second.Get(u); err != nil || c != "Oslo" {
+		t.Fatalf(`expected second.Get(u) == "Oslo", got %v, %s`, c, err)
+	}
+
+	moved, err := second.Modify(u, func(c string) string { return c + "!" })
+	if err != nil {
+		t.Fatalf(`expected second.Modify() to be ok, got %s`, err)
+	}
+	if c, _ := second.Get(moved); c != "Oslo!" {
+		t.Fatalf(`expected second.Get(moved) == "Oslo!", got %s`, c)
+	}
+	if c, _ := second.Get(u); c != "Oslo" {
+		t.Fatalf(`expected the original user to be unchanged, got %s`, c)
+	}
+
+	first, _ := u.Addresses.Get(0)
+	if x, _ := moved.Addresses.Get(0); x != first {
+		t.Fatalf(`expected the first address to be unchanged`)
+	}
+}
+
+func TestIndexErrors(t *testing.T) {
+	u := Alice()
+
+	third := Compose(Compose(addresses, Index[Address](5)), city)
+	if _, err := third.Get(u); err == nil {
+		t.Fatalf(`expected third.Get(u) not to be ok, but was`)
+	}
+	if _, err := third.Set(u, "Rome"); err == nil {
+		t.Fatalf(`expected third.Set(u) not to be ok, but was`)
+	}
+
+	if _, err := Index[int](0).Get(vector.New("x")); err == nil {
+		t.Fatalf(`expected reading a string as an int not to be ok, but was`)
+	}
+}
+
+func TestComposeSetAppends(t *testing.T) {
+	u := Alice()
+	third := Compose(Compose(addresses, Index[Address](2)), city)
+
+	moved, err := third.Set(u, "Rome")
+	if err != nil {
+		t.Fatalf(`expected third.Set(u) to be ok, got %s`, err)
+	}
+	if c, _ := third.Get(moved); c != "Rome" || moved.Addresses.Count() != 3 {
+		t.Fatalf(`expected a third address in Rome, got %v`, moved.Addresses)
+	}
+	if u.Addresses.Count() != 2 {
+		t.Fatalf(`expected the original user to be unchanged`)
+	}
+
+	appended, err := Compose(addresses, Index[Address](2)).Set(u, Address{"Rome"})
+	if err != nil || appended.Addresses.Count() != 3 {
+		t.Fatalf(`expected setting the append position to append, got %v, %s`, appended.Addresses, err)
+	}
+
+	if _, err := third.Modify(u, func(c string) string { return c }); err == nil {
+		t.Fatalf(`expected third.Modify(u) not to be ok, but was`)
+	}
+}
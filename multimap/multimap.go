@@ -0,0 +1,140 @@
+package multimap
+
+import (
+	"../hashmap"
+)
+
+// Values storable in the multimap
+type Value = interface{}
+
+// Persistent map from keys to sets of values.
+// Keys and values must be comparable with == and hashable by hashmap.Hash.
+type Multimap struct {
+	// The map from each key to its non-empty *hashmap.Set of values
+	Map *hashmap.Map
+	// The number of key/value pairs in the multimap
+	Length uint32
+}
+
+// Value for the empty multimap
+var empty = &Multimap{Map: hashmap.New()}
+
+// Return a new empty multimap.
+// Complexity: O(1)
+func New() *Multimap {
+	return empty
+}
+
+// Return the number of key/value pairs in this multimap.
+// Complexity: O(1)
+func (mm *Multimap) Count() uint32 {
+	return mm.Length
+}
+
+// Return the number of distinct keys in this multimap.
+// Complexity: O(1)
+func (mm *Multimap) CountKeys() uint32 {
+	return mm.Map.Count()
+}
+
+// Return the set of values for key, which is empty if key is not present.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (mm *Multimap) Get(key Value) *hashmap.Set {
+	if set, ok := mm.Map.Get(key); ok {
+		return set.(*hashmap.Set)
+	}
+	return hashmap.NewSet()
+}
+
+// Return the number of values for key.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (mm *Multimap) CountKey(key Value) uint32 {
+	return mm.Get(key).Count()
+}
+
+// Return true if value is one of the values for key.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (mm *Multimap) Contains(key, value Value) bool {
+	return mm.Get(key).Contains(value)
+}
+
+// Add value to the values for key.
+// A new multimap is returned, sharing memory with the original.
+// Adding a pair that is already present returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (mm *Multimap) Add(key, value Value) *Multimap {
+	set := mm.Get(key)
+	if set.Contains(value) {
+		return mm
+	}
+
+	return &Multimap{
+		Map:    mm.Map.Assoc(key, set.Add(value)),
+		Length: mm.Length + 1,
+	}
+}
+
+// Remove value from the values for key, removing key if none remain.
+// A new multimap is returned, sharing memory with the original.
+// Removing a pair that is not present returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (mm *Multimap) Remove(key, value Value) *Multimap {
+	set := mm.Get(key)
+	if !set.Contains(value) {
+		return mm
+	}
+
+	set = set.Remove(value)
+	if set.Count() == 0 {
+		return &Multimap{Map: mm.Map.Dissoc(key), Length: mm.Length - 1}
+	}
+	return &Multimap{Map: mm.Map.Assoc(key, set), Length: mm.Length - 1}
+}
+
+// Remove key and all of its values.
+// A new multimap is returned, sharing memory with the original.
+// Removing a key that is not present returns itself.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func (mm *Multimap) RemoveKey(key Value) *Multimap {
+	n := mm.CountKey(key)
+	if n == 0 {
+		return mm
+	}
+	return &Multimap{Map: mm.Map.Dissoc(key), Length: mm.Length - n}
+}
+
+// Return the pairs in either this multimap or other.
+// A new multimap is returned, sharing the value sets of keys found in only
+// one of the originals.
+// Complexity: O(n + m)
+func (mm *Multimap) Union(other *Multimap) *Multimap {
+	var overlap uint32
+
+	m := mm.Map.Merge(other.Map, func(key, a, b hashmap.Value) hashmap.Value {
+		x, y := a.(*hashmap.Set), b.(*hashmap.Set)
+		union := x.Union(y)
+		overlap += x.Count() + y.Count() - union.Count()
+		return union
+	})
+
+	return &Multimap{Map: m, Length: mm.Length + other.Length - overlap}
+}
+
+// Call fn with each key and value, until fn returns false.
+// Complexity: O(n)
+func (mm *Multimap) Each(fn func(key, value Value) bool) {
+	mm.Map.Each(func(key, set hashmap.Value) bool {
+		ok := true
+		set.(*hashmap.Set).Each(func(value hashmap.Value) bool {
+			ok = fn(key, value)
+			return ok
+		})
+		return ok
+	})
+}
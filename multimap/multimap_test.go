@@ -0,0 +1,73 @@
+package multimap
+
+import (
+	"testing"
+)
+
+func AssertPairs(t *testing.T, mm *Multimap, pairs map[Value][]Value) {
+	var n uint32
+	for k, vs := range pairs {
+		if mm.CountKey(k) != uint32(len(vs)) {
+			t.Fatalf(`expected mm.CountKey(%v) == %d, got %d`, k, len(vs), mm.CountKey(k))
+		}
+		for _, v := range vs {
+			if !mm.Contains(k, v) {
+				t.Fatalf(`expected mm.Contains(%v, %v), but did not`, k, v)
+			}
+		}
+		n += uint32(len(vs))
+	}
+
+	if mm.Count() != n {
+		t.Fatalf(`expected mm.Count() == %d, got %d`, n, mm.Count())
+	}
+	if mm.CountKeys() != uint32(len(pairs)) {
+		t.Fatalf(`expected mm.CountKeys() == %d, got %d`, len(pairs), mm.CountKeys())
+	}
+
+	var visited uint32
+	mm.Each(func(k, v Value) bool {
+		visited++
+		return true
+	})
+	if visited != n {
+		t.Fatalf(`expected mm.Each to visit %d pairs, got %d`, n, visited)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	mm := New().Add("a", 1).Add("a", 2).Add("b", 1)
+	cpy := mm.Add("a", 3)
+
+	AssertPairs(t, mm, map[Value][]Value{"a": {1, 2}, "b": {1}})
+	AssertPairs(t, cpy, map[Value][]Value{"a": {1, 2, 3}, "b": {1}})
+
+	if mm.Add("a", 1) != mm {
+		t.Fatalf(`expected adding an existing pair to return itself`)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	mm := New().Add("a", 1).Add("a", 2).Add("b", 1)
+
+	cpy := mm.Remove("a", 1).Remove("b", 1)
+	AssertPairs(t, cpy, map[Value][]Value{"a": {2}})
+	AssertPairs(t, mm, map[Value][]Value{"a": {1, 2}, "b": {1}})
+
+	if mm.Remove("c", 1) != mm || mm.Remove("a", 3) != mm {
+		t.Fatalf(`expected removing a missing pair to return itself`)
+	}
+
+	AssertPairs(t, mm.RemoveKey("a"), map[Value][]Value{"b": {1}})
+}
+
+func TestUnion(t *testing.T) {
+	a := New().Add("x", 1).Add("x", 2).Add("y", 1)
+	b := New().Add("x", 2).Add("x", 3).Add("z", 9)
+
+	AssertPairs(
+		t, a.Union(b),
+		map[Value][]Value{"x": {1, 2, 3}, "y": {1}, "z": {9}},
+	)
+	AssertPairs(t, a, map[Value][]Value{"x": {1, 2}, "y": {1}})
+}
@@ -0,0 +1,203 @@
+package orderedmap
+
+import (
+	"../hashmap"
+	"../vector"
+)
+
+// Values storable in the map
+type Value = interface{}
+
+// A key and value stored in insertion order
+type Entry struct {
+	// The key of the entry
+	Key Value
+	// The value of the entry
+	Value Value
+}
+
+// Sentinel type for deleted entries
+type deletedSentinel struct{}
+
+// Sentinel marking deleted entries in the middle of the vector
+var deleted = &deletedSentinel{}
+
+// Persistent map remembering the order in which keys were first inserted.
+// Entries are appended to a vector.Vector and located through a hash index
+// of their positions. Deleted entries are tombstoned, and dropped from the
+// vector once they reach either end.
+type OrderedMap struct {
+	// The map from each key to the sequence number of its entry
+	Index *hashmap.Map
+	// The entries, or tombstones, in insertion order
+	Entries *vector.Vector
+	// The sequence number of the first element of Entries
+	Base uint32
+}
+
+// Value for the empty map
+var empty = &OrderedMap{
+	Index:   hashmap.New(),
+	Entries: vector.New(),
+}
+
+// Return a new map containing the key/value pairs in kvs..., in order.
+// Complexity: O(n*log(n))
+func New(kvs ...Value) *OrderedMap {
+	if len(kvs)%2 != 0 {
+		panic("orderedmap.New requires an even number of arguments")
+	}
+
+	acc := empty
+	for i := 0; i < len(kvs); i += 2 {
+		acc = acc.Assoc(kvs[i], kvs[i+1])
+	}
+	return acc
+}
+
+// Return the number of keys in this map.
+// Complexity: O(1)
+func (m *OrderedMap) Count() uint32 {
+	return m.Index.Count()
+}
+
+// Return the position of key in the entries vector.
+// Complexity: O(log32(n))
+func (m *OrderedMap) position(key Value) (uint32, bool) {
+	seq, ok := m.Index.Get(key)
+	if !ok {
+		return 0, false
+	}
+	return seq.(uint32) - m.Base, true
+}
+
+// Get the value stored for key.
+// Returns false if key is not in the map.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (m *OrderedMap) Get(key Value) (Value, bool) {
+	pos, ok := m.position(key)
+	if !ok {
+		return nil, false
+	}
+
+	e, err := m.Entries.Get(pos)
+	if err != nil {
+		panic(err)
+	}
+	return e.(*Entry).Value, true
+}
+
+// Get the value stored for key, as Get.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (m *OrderedMap) Lookup(key Value) (Value, bool) {
+	return m.Get(key)
+}
+
+// Associate key with value.
+// New keys are added at the end; existing keys keep their position.
+// A new map is returned, sharing memory with the original.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (m *OrderedMap) Assoc(key, value Value) *OrderedMap {
+	entry := &Entry{Key: key, Value: value}
+
+	if pos, ok := m.position(key); ok {
+		entries, err := m.Entries.Set(pos, entry)
+		if err != nil {
+			panic(err)
+		}
+		return &OrderedMap{Index: m.Index, Entries: entries, Base: m.Base}
+	}
+
+	return &OrderedMap{
+		Index:   m.Index.Assoc(key, m.Base+m.Entries.Count()),
+		Entries: m.Entries.Append(entry),
+		Base:    m.Base,
+	}
+}
+
+// Remove key from the map.
+// A new map is returned, sharing memory with the original.
+// Removing a key that is not in the map returns itself.
+// Complexity: O(log(n)) amortized
+// Effectively: O(1)
+func (m *OrderedMap) Dissoc(key Value) *OrderedMap {
+	pos, ok := m.position(key)
+	if !ok {
+		return m
+	}
+
+	index := m.Index.Dissoc(key)
+	entries, err := m.Entries.Set(pos, deleted)
+	if err != nil {
+		panic(err)
+	}
+	base := m.Base
+
+	// drop dead entries at the head
+	var head uint32
+	for head < entries.Count() && isDeleted(entries, head) {
+		head++
+	}
+	entries = entries.Drop(head)
+	base += head
+
+	// truncate dead entries at the tail
+	tail := entries.Count()
+	for tail > 0 && isDeleted(entries, tail-1) {
+		tail--
+	}
+	entries = entries.Truncate(tail)
+
+	if entries.Count() == 0 {
+		return empty
+	}
+
+	into := &OrderedMap{Index: index, Entries: entries, Base: base}
+	if entries.Count() > 2*index.Count() {
+		// tombstones in the middle dominate, so rebuild
+		into = into.compact()
+	}
+	return into
+}
+
+// Return true if the entry at pos in entries is a tombstone.
+// Complexity: O(log(n))
+func isDeleted(entries *vector.Vector, pos uint32) bool {
+	e, err := entries.Get(pos)
+	if err != nil {
+		panic(err)
+	}
+	return e == deleted
+}
+
+// Return an equivalent map without tombstones.
+// Complexity: O(n*log(n))
+func (m *OrderedMap) compact() *OrderedMap {
+	acc := empty
+	m.Each(func(k, v Value) bool {
+		acc = acc.Assoc(k, v)
+		return true
+	})
+	return acc
+}
+
+// Call fn with each key and value in insertion order, until fn returns false.
+// Complexity: O(n*log(n))
+// Effectively: O(n)
+func (m *OrderedMap) Each(fn func(key, value Value) bool) {
+	for i := uint32(0); i < m.Entries.Count(); i++ {
+		e, err := m.Entries.Get(i)
+		if err != nil {
+			panic(err)
+		}
+		if e == deleted {
+			continue
+		}
+		if entry := e.(*Entry); !fn(entry.Key, entry.Value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package orderedmap
+
+import (
+	"testing"
+)
+
+func AssertOrder(t *testing.T, m *OrderedMap, keys []Value, values []Value) {
+	var (
+		foundKeys   []Value
+		foundValues []Value
+	)
+	m.Each(func(k, v Value) bool {
+		foundKeys = append(foundKeys, k)
+		foundValues = append(foundValues, v)
+		return true
+	})
+
+	if len(foundKeys) != len(keys) {
+		t.Fatalf(`expected keys %v, got %v`, keys, foundKeys)
+	}
+	for i, k := range keys {
+		if foundKeys[i] != k || foundValues[i] != values[i] {
+			t.Fatalf(`expected %v => %v, got %v => %v`, keys, values, foundKeys, foundValues)
+		}
+		if v, ok := m.Get(k); !ok || v != values[i] {
+			t.Fatalf(`expected m.Get(%v) == %v, got %v`, k, values[i], v)
+		}
+	}
+
+	if m.Count() != uint32(len(keys)) {
+		t.Fatalf(`expected m.Count() == %d, got %d`, len(keys), m.Count())
+	}
+}
+
+func TestAssocKeepsInsertionOrder(t *testing.T) {
+	m := New("z", 1, "a", 2, "m", 3)
+	cpy := m.Assoc("a", 20).Assoc("b", 4)
+
+	AssertOrder(t, m, []Value{"z", "a", "m"}, []Value{1, 2, 3})
+	AssertOrder(t, cpy, []Value{"z", "a", "m", "b"}, []Value{1, 20, 3, 4})
+
+	if _, ok := m.Get("b"); ok {
+		t.Fatalf(`expected m.Get("b") not to be ok, but was`)
+	}
+}
+
+func TestDissocMiddle(t *testing.T) {
+	m := New("a", 1, "b", 2, "c", 3)
+	cpy := m.Dissoc("b")
+
+	AssertOrder(t, cpy, []Value{"a", "c"}, []Value{1, 3})
+	AssertOrder(t, m, []Value{"a", "b", "c"}, []Value{1, 2, 3})
+
+	if cpy.Dissoc("b") != cpy {
+		t.Fatalf(`expected dissociating a missing key to return itself`)
+	}
+
+	cpy = cpy.Assoc("b", 5)
+	AssertOrder(t, cpy, []Value{"a", "c", "b"}, []Value{1, 3, 5})
+}
+
+func TestDissocCompactsHeadAndTail(t *testing.T) {
+	m := New("a", 1, "b", 2, "c", 3, "d", 4, "e", 5)
+
+	cpy := m.Dissoc("b").Dissoc("a")
+	if cpy.Entries.Count() != 3 || cpy.Base != 2 {
+		t.Fatalf(`expected dead head entries to be dropped, got %d entries`, cpy.Entries.Count())
+	}
+	AssertOrder(t, cpy, []Value{"c", "d", "e"}, []Value{3, 4, 5})
+
+	cpy = cpy.Dissoc("d").Dissoc("e")
+	if cpy.Entries.Count() != 1 {
+		t.Fatalf(`expected dead tail entries to be truncated, got %d entries`, cpy.Entries.Count())
+	}
+	AssertOrder(t, cpy, []Value{"c"}, []Value{3})
+
+	cpy = cpy.Assoc("f", 6).Assoc("c", 30)
+	AssertOrder(t, cpy, []Value{"c", "f"}, []Value{30, 6})
+}
+
+func TestDissocManyCompacts(t *testing.T) {
+	m := New()
+	for i := 0; i < 1000; i++ {
+		m = m.Assoc(i, i)
+	}
+	for i := 1; i < 999; i++ {
+		if i%10 != 0 {
+			m = m.Dissoc(i)
+		}
+	}
+
+	if m.Entries.Count() > 2*m.Count() {
+		t.Fatalf(`expected tombstones to be compacted, got %d entries`, m.Entries.Count())
+	}
+
+	keys := []Value{0}
+	for i := 10; i < 999; i += 10 {
+		keys = append(keys, i)
+	}
+	keys = append(keys, 999)
+	AssertOrder(t, m, keys, keys)
+}
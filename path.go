@@ -0,0 +1,207 @@
+package persistent
+
+import (
+	"./hashmap"
+	"./vector"
+	"math"
+)
+
+// Get the value at path in nested vectors and maps.
+// Vectors are indexed by non-negative integers of any type, and maps by key.
+// An empty path returns coll itself. A []Value path, as taken by SetIn,
+// UpdateIn and DeleteIn, can be passed as GetIn(coll, path...).
+// Complexity: O(d) for path length d, with effectively O(1) per step
+func GetIn(coll Value, path ...Value) (Value, error) {
+	for depth := range path {
+		v, err := get(coll, path, depth)
+		if err != nil {
+			return nil, err
+		}
+		coll = v
+	}
+	return coll, nil
+}
+
+// Set the value at path in nested vectors and maps.
+// Every collection above the last key must exist. The last key may be new
+// in a map, or the append position in a vector.
+// A new root is returned, sharing everything off the path with the original.
+// Complexity: O(d) for path length d, with effectively O(1) per step
+func SetIn(coll Value, path []Value, value Value) (Value, error) {
+	return editIn(coll, path, 0, func(parent Value) (Value, error) {
+		return set(parent, path, len(path)-1, value)
+	})
+}
+
+// Replace the value at path with the result of fn.
+// A missing value is a KeyNotFound error.
+// A new root is returned, sharing everything off the path with the original.
+// Complexity: O(d) for path length d, with effectively O(1) per step
+func UpdateIn(coll Value, path []Value, fn func(Value) Value) (Value, error) {
+	return editIn(coll, path, 0, func(parent Value) (Value, error) {
+		old, err := get(parent, path, len(path)-1)
+		if err != nil {
+			return nil, err
+		}
+		return set(parent, path, len(path)-1, fn(old))
+	})
+}
+
+// Delete the value at path, shifting later elements down in a vector.
+// Deleting a missing map key leaves the map unchanged.
+// A new root is returned, sharing everything off the path with the original.
+// Complexity: O(d) for path length d, plus O(min(i, n - i)) to delete the
+// i-th of n elements of a vector
+func DeleteIn(coll Value, path []Value) (Value, error) {
+	return editIn(coll, path, 0, func(parent Value) (Value, error) {
+		return remove(parent, path, len(path)-1)
+	})
+}
+
+// Apply fn to the collection holding the last key in path, rebuilding each
+// collection from depth down.
+// Complexity: O(d) for path length d
+func editIn(coll Value, path []Value, depth int, fn func(Value) (Value, error)) (Value, error) {
+	if len(path) == 0 {
+		return nil, &EmptyPath{}
+	}
+	if depth == len(path)-1 {
+		return fn(coll)
+	}
+
+	child, err := get(coll, path, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := editIn(child, path, depth+1, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return set(coll, path, depth, updated)
+}
+
+// Return a copy of path up to and including path[depth], for errors to keep
+// without aliasing the caller's slice.
+// Complexity: O(d)
+func prefix(path []Value, depth int) []Value {
+	return append([]Value(nil), path[:depth+1]...)
+}
+
+// Return key as a vector index, or false if it is not a valid index.
+// Complexity: O(1)
+func index(key Value) (uint32, bool) {
+	var i int64
+	switch k := key.(type) {
+	case int:
+		i = int64(k)
+	case int8:
+		i = int64(k)
+	case int16:
+		i = int64(k)
+	case int32:
+		i = int64(k)
+	case int64:
+		i = k
+	case uint8:
+		i = int64(k)
+	case uint16:
+		i = int64(k)
+	case uint32:
+		return k, true
+	case uint:
+		if uint64(k) > math.MaxUint32 {
+			return 0, false
+		}
+		i = int64(k)
+	case uint64:
+		if k > math.MaxUint32 {
+			return 0, false
+		}
+		i = int64(k)
+	default:
+		return 0, false
+	}
+
+	if i < 0 || i > math.MaxUint32 {
+		return 0, false
+	}
+	return uint32(i), true
+}
+
+// Get the value for path[depth] in coll.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func get(coll Value, path []Value, depth int) (Value, error) {
+	key := path[depth]
+
+	switch c := coll.(type) {
+	case *vector.Vector:
+		i, ok := index(key)
+		if !ok {
+			return nil, &InvalidKey{prefix(path, depth)}
+		}
+		v, err := c.Get(i)
+		if err != nil {
+			return nil, &KeyNotFound{prefix(path, depth)}
+		}
+		return v, nil
+	case *hashmap.Map:
+		v, ok := c.Get(key)
+		if !ok {
+			return nil, &KeyNotFound{prefix(path, depth)}
+		}
+		return v, nil
+	}
+
+	return nil, &NotAssociative{prefix(path, depth)}
+}
+
+// Set path[depth] in coll to value.
+// Complexity: O(log32(n))
+// Effectively: O(1)
+func set(coll Value, path []Value, depth int, value Value) (Value, error) {
+	key := path[depth]
+
+	switch c := coll.(type) {
+	case *vector.Vector:
+		i, ok := index(key)
+		if !ok {
+			return nil, &InvalidKey{prefix(path, depth)}
+		}
+		v, err := c.Set(i, value)
+		if err != nil {
+			return nil, &KeyNotFound{prefix(path, depth)}
+		}
+		return v, nil
+	case *hashmap.Map:
+		return c.Assoc(key, value), nil
+	}
+
+	return nil, &NotAssociative{prefix(path, depth)}
+}
+
+// Remove path[depth] from coll.
+// Complexity: O(min(i, n - i)) for the i-th of n elements of a vector
+func remove(coll Value, path []Value, depth int) (Value, error) {
+	key := path[depth]
+
+	switch c := coll.(type) {
+	case *vector.Vector:
+		i, ok := index(key)
+		if !ok {
+			return nil, &InvalidKey{prefix(path, depth)}
+		}
+
+		v, err := c.Remove(i)
+		if err != nil {
+			return nil, &KeyNotFound{prefix(path, depth)}
+		}
+		return v, nil
+	case *hashmap.Map:
+		return c.Dissoc(key), nil
+	}
+
+	return nil, &NotAssociative{prefix(path, depth)}
+}
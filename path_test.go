@@ -0,0 +1,135 @@
+package persistent
+
+import (
+	"./hashmap"
+	"./vector"
+	"testing"
+)
+
+// The document {"name": "x", "tags": ["a", "b", "c"], "meta": {"n": 1}}
+func Document() *hashmap.Map {
+	return hashmap.New(
+		"name", "x",
+		"tags", Vector("a", "b", "c"),
+		"meta", hashmap.New("n", 1),
+	)
+}
+
+func TestGetIn(t *testing.T) {
+	doc := Document()
+
+	if v, err := GetIn(doc, "tags", 1); err != nil || v != "b" {
+		t.Fatalf(`expected GetIn(doc, "tags", 1) == "b", got %v, %s`, v, err)
+	}
+	if v, err := GetIn(doc); err != nil || v != doc {
+		t.Fatalf(`expected GetIn(doc) to return doc`)
+	}
+
+	_, err := GetIn(doc, "tags", 7)
+	if e, ok := err.(*KeyNotFound); !ok || len(e.Path) != 2 || e.Path[1] != 7 {
+		t.Fatalf(`expected a KeyNotFound error at tags/7, got %v`, err)
+	}
+
+	_, err = GetIn(doc, "tags", "first")
+	if _, ok := err.(*InvalidKey); !ok {
+		t.Fatalf(`expected an InvalidKey error, got %v`, err)
+	}
+
+	_, err = GetIn(doc, "name", "first", "x")
+	if e, ok := err.(*NotAssociative); !ok || len(e.Path) != 2 {
+		t.Fatalf(`expected a NotAssociative error at name/first, got %v`, err)
+	}
+}
+
+func TestSetIn(t *testing.T) {
+	doc := Document()
+
+	updated, err := SetIn(doc, []Value{"tags", 3}, "d")
+	if err != nil {
+		t.Fatalf(`expected SetIn() to be ok, got %s`, err)
+	}
+	if v, _ := GetIn(updated, "tags", 3); v != "d" {
+		t.Fatalf(`expected tags/3 == "d", got %v`, v)
+	}
+	if _, err := GetIn(doc, "tags", 3); err == nil {
+		t.Fatalf(`expected the original document to be unchanged`)
+	}
+
+	before, _ := doc.Get("meta")
+	after, _ := updated.(*hashmap.Map).Get("meta")
+	if before != after {
+		t.Fatalf(`expected meta to be shared by both documents`)
+	}
+
+	if _, err := SetIn(doc, []Value{"missing", "x"}, 1); err == nil {
+		t.Fatalf(`expected setting beneath a missing key not to be ok, but was`)
+	}
+	if _, err := SetIn(doc, nil, 1); err == nil {
+		t.Fatalf(`expected setting an empty path not to be ok, but was`)
+	}
+}
+
+func TestUpdateIn(t *testing.T) {
+	inc := func(v Value) Value {
+		return v.(int) + 1
+	}
+
+	updated, err := UpdateIn(Document(), []Value{"meta", "n"}, inc)
+	if err != nil {
+		t.Fatalf(`expected UpdateIn() to be ok, got %s`, err)
+	}
+	if v, _ := GetIn(updated, "meta", "n"); v != 2 {
+		t.Fatalf(`expected meta/n == 2, got %v`, v)
+	}
+
+	if _, err := UpdateIn(Document(), []Value{"meta", "m"}, inc); err == nil {
+		t.Fatalf(`expected updating a missing key not to be ok, but was`)
+	}
+}
+
+func TestDeleteIn(t *testing.T) {
+	for i, expected := range []string{"bc", "ac", "ab"} {
+		updated, err := DeleteIn(Document(), []Value{"tags", i})
+		if err != nil {
+			t.Fatalf(`expected DeleteIn() to be ok, got %s`, err)
+		}
+
+		tags, _ := GetIn(updated, "tags")
+		s := ""
+		tags.(*vector.Vector).Each(func(v Value) bool {
+			s += v.(string)
+			return true
+		})
+		if s != expected {
+			t.Fatalf(`expected tags == %s, got %s`, expected, s)
+		}
+	}
+
+	updated, _ := DeleteIn(Document(), []Value{"meta", "n"})
+	if v, _ := GetIn(updated, "meta"); v.(*hashmap.Map).Count() != 0 {
+		t.Fatalf(`expected meta to be empty`)
+	}
+
+	if _, err := DeleteIn(Document(), []Value{"tags", 3}); err == nil {
+		t.Fatalf(`expected deleting a missing index not to be ok, but was`)
+	}
+}
+
+func TestErrorPathIsACopy(t *testing.T) {
+	path := []Value{"tags", 7, "x"}
+	_, err := SetIn(Document(), path, 1)
+	path[0], path[1] = "meta", 8
+
+	e, ok := err.(*KeyNotFound)
+	if !ok || len(e.Path) != 2 || e.Path[0] != "tags" || e.Path[1] != 7 {
+		t.Fatalf(`expected a KeyNotFound error at tags/7, got %v`, err)
+	}
+
+	path = []Value{"tags", 7}
+	_, err = GetIn(Document(), path...)
+	path[1] = 8
+
+	if e, ok := err.(*KeyNotFound); !ok || e.Path[1] != 7 {
+		t.Fatalf(`expected a KeyNotFound error at tags/7, got %v`, err)
+	}
+}
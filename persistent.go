@@ -4,7 +4,76 @@ import (
 	"./vector"
 )
 
+// Values storable in persistent collections
+type Value = vector.Value
+
+// Collections that know their size
+type Counted interface {
+	// Return the number of elements in the collection
+	Count() uint32
+}
+
+// Collections with elements at positions 0...Count()-1
+type Indexed interface {
+	Counted
+	// Get the element at key, or an error if key is out of bounds
+	Get(key uint32) (Value, error)
+}
+
+// Collections whose elements can be visited in order
+type Seqable interface {
+	// Call fn with each element in order, until fn returns false
+	Each(fn func(Value) bool)
+}
+
+// Collections with a top element that can be removed, returning S
+type Stack[S any] interface {
+	// Get the top element, or an error if the stack is empty
+	Peek() (Value, error)
+	// Return the stack without its top element
+	Pop() S
+}
+
+// Collections mapping keys of type K to values, returning A when updated
+type Associative[K any, A any] interface {
+	// Get the value for key, or false if key is not present
+	Lookup(key K) (Value, bool)
+	// Return the collection with key associated with value
+	Assoc(key K, value Value) A
+}
+
+// Collections that values can be added to, returning C
+type Collection[C any] interface {
+	Counted
+	// Return the collection with value added in its natural position
+	Conj(value Value) C
+	// Return an empty collection of the same kind
+	Empty() C
+}
+
 // Return a new persistent vector with specified elements.
 func Vector(elements ...vector.Value) *vector.Vector {
 	return vector.New(elements...)
 }
+
+// Return to with each element of from added in order.
+// Complexity: O(n) calls to Conj
+func Into[C Collection[C]](to C, from Seqable) C {
+	from.Each(func(v Value) bool {
+		to = to.Conj(v)
+		return true
+	})
+	return to
+}
+
+// Return the result of calling fn with the accumulated value and each
+// element of coll in order, starting from init.
+// Complexity: O(n)
+func Reduce(coll Seqable, init Value, fn func(acc, v Value) Value) Value {
+	acc := init
+	coll.Each(func(v Value) bool {
+		acc = fn(acc, v)
+		return true
+	})
+	return acc
+}
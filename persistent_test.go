@@ -1,9 +1,149 @@
 package persistent
 
 import (
+	"./bag"
+	"./bimap"
+	"./bitset"
+	"./fingertree"
+	"./grid"
+	"./hashmap"
+	"./heap"
+	"./interval"
+	"./intmap"
+	"./multimap"
+	"./orderedmap"
+	"./radix"
+	"./rtree"
+	"./sortedset"
+	"./transducer"
+	"./vector"
 	"testing"
 )
 
+var (
+	_ Indexed                             = (*vector.Vector)(nil)
+	_ Seqable                             = (*vector.Vector)(nil)
+	_ Stack[*vector.Vector]               = (*vector.Vector)(nil)
+	_ Associative[uint32, *vector.Vector] = (*vector.Vector)(nil)
+	_ Collection[*vector.Vector]          = (*vector.Vector)(nil)
+	_ Seqable                             = (*Seq)(nil)
+
+	_ Associative[Value, *hashmap.Map]           = (*hashmap.Map)(nil)
+	_ Associative[Value, *orderedmap.OrderedMap] = (*orderedmap.OrderedMap)(nil)
+	_ Associative[uint64, *intmap.IntMap]        = (*intmap.IntMap)(nil)
+	_ Associative[string, *radix.Tree]           = (*radix.Tree)(nil)
+
+	_ Seqable = (*fingertree.FingerTree)(nil)
+	_ Seqable = (*hashmap.Set)(nil)
+	_ Seqable = (*sortedset.SortedSet)(nil)
+	_ Seqable = transducer.Slice(nil)
+
+	_ Counted = (*bag.Bag)(nil)
+	_ Counted = (*bimap.BiMap)(nil)
+	_ Counted = (*bitset.Bitset)(nil)
+	_ Counted = (*grid.Grid)(nil)
+	_ Counted = (*hashmap.Map)(nil)
+	_ Counted = (*hashmap.Set)(nil)
+	_ Counted = (*heap.Heap)(nil)
+	_ Counted = (*interval.Tree)(nil)
+	_ Counted = (*intmap.IntMap)(nil)
+	_ Counted = (*multimap.Multimap)(nil)
+	_ Counted = (*orderedmap.OrderedMap)(nil)
+	_ Counted = (*radix.Tree)(nil)
+	_ Counted = (*rtree.Tree)(nil)
+	_ Counted = (*sortedset.SortedSet)(nil)
+)
+
 func TestVector(t *testing.T) {
 	Vector()
 }
+
+func TestInto(t *testing.T) {
+	vec := Into(Vector(1, 2), Vector(3, 4))
+
+	if vec.Count() != 4 {
+		t.Fatalf(`expected vec.Count() == 4, got %d`, vec.Count())
+	}
+	if x, _ := vec.Get(3); x != 4 {
+		t.Fatalf(`expected vec.Get(3) == 4, got %v`, x)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	sum := Reduce(Vector(1, 2, 3), 0, func(acc, v Value) Value {
+		return acc.(int) + v.(int)
+	})
+
+	if sum != 6 {
+		t.Fatalf(`expected sum == 6, got %v`, sum)
+	}
+}
+
+// Return the top of any stack, or nil when empty
+func top[S Stack[S]](s S) Value {
+	v, _ := s.Peek()
+	return v
+}
+
+func TestGenericStack(t *testing.T) {
+	vec := Vector(1, 2, 3)
+
+	if top(vec) != 3 || top(vec.Pop()) != 2 {
+		t.Fatalf(`expected the tops of vec to be 3 then 2`)
+	}
+}
+
+// Return m with the value at key replaced by fn of it, if present
+func update[K any, A Associative[K, A]](m A, key K, fn func(Value) Value) A {
+	if v, ok := m.Lookup(key); ok {
+		return m.Assoc(key, fn(v))
+	}
+	return m
+}
+
+func TestGenericAssociative(t *testing.T) {
+	inc := func(v Value) Value {
+		return v.(int) + 1
+	}
+
+	vec := update[uint32](Vector(1, 2, 3), 1, inc)
+	if x, _ := vec.Get(1); x != 3 {
+		t.Fatalf(`expected vec.Get(1) == 3, got %v`, x)
+	}
+
+	m := update[Value](hashmap.New("a", 1), "a", inc)
+	if x, _ := m.Get("a"); x != 2 {
+		t.Fatalf(`expected m.Get("a") == 2, got %v`, x)
+	}
+
+	im := update[uint64](intmap.New().Insert(7, 1), 7, inc)
+	if x, _ := im.Lookup(7); x != 2 {
+		t.Fatalf(`expected im.Lookup(7) == 2, got %v`, x)
+	}
+
+	if m = update[Value](m, "b", inc); m.Count() != 1 {
+		t.Fatalf(`expected a missing key to be left out, got %d keys`, m.Count())
+	}
+}
+
+func TestReduceAcrossCollections(t *testing.T) {
+	sum := func(acc, v Value) Value {
+		return acc.(int) + v.(int)
+	}
+	cmp := func(a, b Value) int {
+		return a.(int) - b.(int)
+	}
+
+	colls := []Seqable{
+		Vector(1, 2, 3),
+		sortedset.New(cmp, 3, 1, 2),
+		hashmap.NewSet(2, 3, 1),
+		fingertree.New(fingertree.Size, 1, 2, 3),
+	}
+
+	for _, coll := range colls {
+		if n := Reduce(coll, 0, sum); n != 6 {
+			t.Fatalf(`expected Reduce(%T) == 6, got %v`, coll, n)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package radix
+
+import (
+	"sort"
+)
+
+const (
+	// The number of edges at which a node switches to a dense edge table
+	DENSE = 48
+)
+
+// Representation of a radix tree node.
+// Each node stores the compressed run of bytes leading to it, and adapts its
+// edge storage to its number of children: a sorted list of labels while
+// small, and a table indexed by byte once it holds more than DENSE edges.
+type Node struct {
+	// The bytes consumed by this node, starting with its edge label
+	Prefix string
+	// True if a key ends at this node
+	HasValue bool
+	// The value stored for the key ending at this node
+	Value Value
+	// Sorted edge labels of a sparse node
+	labels []byte
+	// Children of a sparse node, in the same order as labels
+	edges []*Node
+	// Children of a dense node, indexed by label
+	dense []*Node
+	// The number of children of this node
+	size int
+}
+
+// Make a shallow copy of this node.
+// This copies the node and its edge storage, but not its children.
+// Complexity: O(1)
+func (node *Node) Copy() *Node {
+	cpy := *node
+	if node.dense != nil {
+		cpy.dense = append([]*Node(nil), node.dense...)
+	} else {
+		cpy.labels = append([]byte(nil), node.labels...)
+		cpy.edges = append([]*Node(nil), node.edges...)
+	}
+	return &cpy
+}
+
+// Return the child along the edge labelled b, or nil.
+// Complexity: O(log(k))
+func (node *Node) child(b byte) *Node {
+	if node.dense != nil {
+		return node.dense[b]
+	}
+	i := sort.Search(len(node.labels), func(i int) bool {
+		return node.labels[i] >= b
+	})
+	if i < len(node.labels) && node.labels[i] == b {
+		return node.edges[i]
+	}
+	return nil
+}
+
+// Return a copy of node with the edge labelled b set to child.
+// Setting a nil child removes the edge.
+// Complexity: O(k)
+func (node *Node) withChild(b byte, child *Node) *Node {
+	cpy := node.Copy()
+
+	if cpy.dense != nil {
+		if cpy.dense[b] == nil {
+			cpy.size++
+		}
+		if child == nil {
+			cpy.size--
+		}
+		cpy.dense[b] = child
+		if cpy.size <= DENSE/2 {
+			cpy.makeSparse()
+		}
+		return cpy
+	}
+
+	i := sort.Search(len(cpy.labels), func(i int) bool {
+		return cpy.labels[i] >= b
+	})
+	found := i < len(cpy.labels) && cpy.labels[i] == b
+
+	switch {
+	case found && child == nil:
+		cpy.labels = append(cpy.labels[:i], cpy.labels[i+1:]...)
+		cpy.edges = append(cpy.edges[:i], cpy.edges[i+1:]...)
+		cpy.size--
+	case found:
+		cpy.edges[i] = child
+	case child != nil:
+		cpy.labels = append(cpy.labels[:i], append([]byte{b}, cpy.labels[i:]...)...)
+		cpy.edges = append(cpy.edges[:i], append([]*Node{child}, cpy.edges[i:]...)...)
+		cpy.size++
+		if cpy.size > DENSE {
+			cpy.makeDense()
+		}
+	}
+
+	return cpy
+}
+
+// Convert sparse edge storage to a dense table.
+// Mutates, on the assumption that node is a copy.
+// Complexity: O(k)
+func (node *Node) makeDense() {
+	node.dense = make([]*Node, 256)
+	for i, b := range node.labels {
+		node.dense[b] = node.edges[i]
+	}
+	node.labels, node.edges = nil, nil
+}
+
+// Convert a dense edge table to sparse storage.
+// Mutates, on the assumption that node is a copy.
+// Complexity: O(1)
+func (node *Node) makeSparse() {
+	node.labels = make([]byte, 0, node.size)
+	node.edges = make([]*Node, 0, node.size)
+	for b, child := range node.dense {
+		if child != nil {
+			node.labels = append(node.labels, byte(b))
+			node.edges = append(node.edges, child)
+		}
+	}
+	node.dense = nil
+}
+
+// Return the only child of a node with exactly one edge.
+// Complexity: O(1)
+func (node *Node) onlyChild() *Node {
+	if node.dense != nil {
+		for _, child := range node.dense {
+			if child != nil {
+				return child
+			}
+		}
+	}
+	return node.edges[0]
+}
+
+// Merge a node with no value and a single child into that child.
+// Remove a node with no value and no children entirely.
+// Complexity: O(k)
+func (node *Node) compress() *Node {
+	if node.HasValue {
+		return node
+	}
+
+	switch node.size {
+	case 0:
+		return nil
+	case 1:
+		child := node.onlyChild().Copy()
+		child.Prefix = node.Prefix + child.Prefix
+		return child
+	}
+	return node
+}
+
+// Visit each child in label order until fn returns false.
+// Complexity: O(k)
+func (node *Node) eachChild(fn func(*Node) bool) bool {
+	edges := node.edges
+	if node.dense != nil {
+		edges = node.dense
+	}
+	for _, child := range edges {
+		if child != nil && !fn(child) {
+			return false
+		}
+	}
+	return true
+}
+
+// Return the length of the common prefix of a and b.
+// Complexity: O(n)
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// Insert key beneath node, returning the new node.
+// Only the path to key is copied.
+// Returns true if the key was not already present.
+// Complexity: O(len(key))
+func insert(node *Node, key string, value Value) (*Node, bool) {
+	if node == nil {
+		return &Node{Prefix: key, HasValue: true, Value: value}, true
+	}
+
+	common := commonPrefix(key, node.Prefix)
+	if common < len(node.Prefix) {
+		// split the compressed path at the point of divergence
+		tail := node.Copy()
+		tail.Prefix = node.Prefix[common:]
+
+		parent := (&Node{Prefix: node.Prefix[:common]}).withChild(tail.Prefix[0], tail)
+		if common == len(key) {
+			parent.HasValue, parent.Value = true, value
+			return parent, true
+		}
+		leaf := &Node{Prefix: key[common:], HasValue: true, Value: value}
+		return parent.withChild(key[common], leaf), true
+	}
+
+	rest := key[common:]
+	if len(rest) == 0 {
+		cpy := node.Copy()
+		cpy.HasValue, cpy.Value = true, value
+		return cpy, !node.HasValue
+	}
+
+	child, added := insert(node.child(rest[0]), rest, value)
+	return node.withChild(rest[0], child), added
+}
+
+// Remove key from beneath node, returning the new node, which may be nil.
+// Returns node itself and false if the key is not present.
+// Complexity: O(len(key))
+func remove(node *Node, key string) (*Node, bool) {
+	if node == nil || len(key) < len(node.Prefix) || key[:len(node.Prefix)] != node.Prefix {
+		return node, false
+	}
+
+	rest := key[len(node.Prefix):]
+	if len(rest) == 0 {
+		if !node.HasValue {
+			return node, false
+		}
+		cpy := node.Copy()
+		cpy.HasValue, cpy.Value = false, nil
+		return cpy.compress(), true
+	}
+
+	child, removed := remove(node.child(rest[0]), rest)
+	if !removed {
+		return node, false
+	}
+	return node.withChild(rest[0], child).compress(), true
+}
+
+// Visit every key and value beneath node in order, where path is the key
+// leading up to node, until fn returns false.
+// Complexity: O(n)
+func walk(node *Node, path string, fn func(string, Value) bool) bool {
+	path += node.Prefix
+	if node.HasValue && !fn(path, node.Value) {
+		return false
+	}
+	return node.eachChild(func(child *Node) bool {
+		return walk(child, path, fn)
+	})
+}
@@ -0,0 +1,140 @@
+package radix
+
+// Values storable in the tree
+type Value = interface{}
+
+// Persistent radix tree mapping string keys to values.
+// Updates copy only the nodes along the path to the key, sharing all other
+// nodes with the original tree.
+type Tree struct {
+	// The root node of the tree, nil when empty
+	Root *Node
+	// The number of keys in the tree
+	Length uint32
+}
+
+// Value for the empty tree
+var empty = &Tree{}
+
+// Return a new empty tree.
+// Complexity: O(1)
+func New() *Tree {
+	return empty
+}
+
+// Return the number of keys in this tree.
+// Complexity: O(1)
+func (tree *Tree) Count() uint32 {
+	return tree.Length
+}
+
+// Get the value stored for key.
+// Returns false if key is not in the tree.
+// Complexity: O(len(key))
+func (tree *Tree) Get(key string) (Value, bool) {
+	node := tree.Root
+	for node != nil {
+		if len(key) < len(node.Prefix) || key[:len(node.Prefix)] != node.Prefix {
+			return nil, false
+		}
+		key = key[len(node.Prefix):]
+		if len(key) == 0 {
+			return node.Value, node.HasValue
+		}
+		node = node.child(key[0])
+	}
+	return nil, false
+}
+
+// Get the value stored for key, as Get.
+// Complexity: O(len(key))
+func (tree *Tree) Lookup(key string) (Value, bool) {
+	return tree.Get(key)
+}
+
+// Insert key into the tree with value, replacing any existing value.
+// A new tree is returned, sharing memory with the original.
+// Complexity: O(len(key))
+func (tree *Tree) Insert(key string, value Value) *Tree {
+	root, added := insert(tree.Root, key, value)
+
+	length := tree.Length
+	if added {
+		length++
+	}
+
+	return &Tree{Root: root, Length: length}
+}
+
+// Associate key with value, as Insert.
+// Complexity: O(len(key))
+func (tree *Tree) Assoc(key string, value Value) *Tree {
+	return tree.Insert(key, value)
+}
+
+// Remove key from the tree.
+// A new tree is returned, sharing memory with the original.
+// Deleting a key that is not in the tree returns itself.
+// Complexity: O(len(key))
+func (tree *Tree) Delete(key string) *Tree {
+	root, removed := remove(tree.Root, key)
+	if !removed {
+		return tree
+	}
+
+	return &Tree{Root: root, Length: tree.Length - 1}
+}
+
+// Find the longest key in the tree that is a prefix of s.
+// Returns false if no key is a prefix of s.
+// Complexity: O(len(s))
+func (tree *Tree) LongestPrefix(s string) (key string, value Value, ok bool) {
+	var consumed int
+
+	node := tree.Root
+	for node != nil {
+		rest := s[consumed:]
+		if len(rest) < len(node.Prefix) || rest[:len(node.Prefix)] != node.Prefix {
+			break
+		}
+		consumed += len(node.Prefix)
+		if node.HasValue {
+			key, value, ok = s[:consumed], node.Value, true
+		}
+		if consumed == len(s) {
+			break
+		}
+		node = node.child(s[consumed])
+	}
+
+	return
+}
+
+// Call fn with each key starting with prefix, and its value, in key order,
+// until fn returns false.
+// Complexity: O(len(prefix) + k)
+func (tree *Tree) WalkPrefix(prefix string, fn func(key string, value Value) bool) {
+	var path string
+
+	node := tree.Root
+	for node != nil {
+		rest := prefix[len(path):]
+		common := commonPrefix(rest, node.Prefix)
+		switch {
+		case common == len(rest):
+			// every key beneath node starts with prefix
+			walk(node, path, fn)
+			return
+		case common < len(node.Prefix):
+			return
+		}
+		path += node.Prefix
+		node = node.child(prefix[len(path)])
+	}
+}
+
+// Call fn with each key and value in key order, until fn returns false.
+// Complexity: O(n)
+func (tree *Tree) Walk(fn func(key string, value Value) bool) {
+	tree.WalkPrefix("", fn)
+}
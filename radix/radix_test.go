@@ -0,0 +1,156 @@
+package radix
+
+import (
+	"testing"
+)
+
+func AssertContains(t *testing.T, tree *Tree, elems map[string]Value) {
+	for k, v := range elems {
+		x, ok := tree.Get(k)
+		if !ok {
+			t.Fatalf(`expected tree.Get(%q) to be ok, but was not`, k)
+		}
+		if x != v {
+			t.Fatalf(`expected tree.Get(%q) == %v, got %v`, k, v, x)
+		}
+	}
+}
+
+func AssertWalk(t *testing.T, tree *Tree, prefix string, keys []string) {
+	var found []string
+	tree.WalkPrefix(prefix, func(k string, v Value) bool {
+		found = append(found, k)
+		return true
+	})
+
+	if len(found) != len(keys) {
+		t.Fatalf(`expected WalkPrefix(%q) to visit %v, got %v`, prefix, keys, found)
+	}
+	for i, k := range keys {
+		if found[i] != k {
+			t.Fatalf(`expected WalkPrefix(%q) to visit %v, got %v`, prefix, keys, found)
+		}
+	}
+}
+
+func TestInsertAndGet(t *testing.T) {
+	tree := New().
+		Insert("/usr", 1).
+		Insert("/usr/local", 2).
+		Insert("/usr/lib", 3).
+		Insert("/etc", 4).
+		Insert("", 5)
+
+	AssertContains(
+		t, tree,
+		map[string]Value{
+			"/usr":       1,
+			"/usr/local": 2,
+			"/usr/lib":   3,
+			"/etc":       4,
+			"":           5,
+		},
+	)
+
+	for _, k := range []string{"/", "/us", "/usr/l", "/usr/local/bin"} {
+		if _, ok := tree.Get(k); ok {
+			t.Fatalf(`expected tree.Get(%q) not to be ok, but was`, k)
+		}
+	}
+
+	if tree.Count() != 5 {
+		t.Fatalf(`expected tree.Count() == 5, got %d`, tree.Count())
+	}
+}
+
+func TestInsertSharesStructure(t *testing.T) {
+	tree := New().Insert("/usr/local", 1).Insert("/etc/hosts", 2)
+	cpy := tree.Insert("/usr/local", 3).Insert("/usr/share", 4)
+
+	AssertContains(t, tree, map[string]Value{"/usr/local": 1, "/etc/hosts": 2})
+	AssertContains(t, cpy, map[string]Value{"/usr/local": 3, "/usr/share": 4, "/etc/hosts": 2})
+
+	if tree.Root.child('e') != cpy.Root.child('e') {
+		t.Fatalf(`expected the untouched /etc branch to be shared`)
+	}
+	if cpy.Count() != 3 {
+		t.Fatalf(`expected cpy.Count() == 3, got %d`, cpy.Count())
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tree := New().Insert("abc", 1).Insert("abd", 2).Insert("ab", 3)
+
+	cpy := tree.Delete("abc")
+	AssertContains(t, cpy, map[string]Value{"abd": 2, "ab": 3})
+	AssertContains(t, tree, map[string]Value{"abc": 1, "abd": 2, "ab": 3})
+
+	cpy = cpy.Delete("ab")
+	AssertContains(t, cpy, map[string]Value{"abd": 2})
+	if cpy.Root.Prefix != "abd" {
+		t.Fatalf(`expected cpy.Root.Prefix == "abd", got %q`, cpy.Root.Prefix)
+	}
+
+	if cpy.Delete("zzz") != cpy {
+		t.Fatalf(`expected deleting a missing key to return itself`)
+	}
+
+	cpy = cpy.Delete("abd")
+	if cpy.Count() != 0 || cpy.Root != nil {
+		t.Fatalf(`expected cpy to be empty`)
+	}
+}
+
+func TestDenseNodes(t *testing.T) {
+	tree := New()
+	for i := 0; i < 256; i++ {
+		tree = tree.Insert(string([]byte{'k', byte(i)}), i)
+	}
+
+	if tree.Root.dense == nil {
+		t.Fatalf(`expected a node with 256 children to be dense`)
+	}
+	for i := 0; i < 256; i++ {
+		AssertContains(t, tree, map[string]Value{string([]byte{'k', byte(i)}): i})
+	}
+
+	for i := 0; i < 250; i++ {
+		tree = tree.Delete(string([]byte{'k', byte(i)}))
+	}
+	if tree.Root.dense != nil {
+		t.Fatalf(`expected a node with 6 children to be sparse`)
+	}
+	AssertWalk(t, tree, "k", []string{"k\xfa", "k\xfb", "k\xfc", "k\xfd", "k\xfe", "k\xff"})
+}
+
+func TestLongestPrefix(t *testing.T) {
+	tree := New().Insert("/", 1).Insert("/api", 2).Insert("/api/v1", 3)
+
+	key, value, ok := tree.LongestPrefix("/api/v1/users")
+	if !ok || key != "/api/v1" || value != 3 {
+		t.Fatalf(`expected LongestPrefix == "/api/v1", got %q`, key)
+	}
+
+	key, value, ok = tree.LongestPrefix("/apple")
+	if !ok || key != "/" || value != 1 {
+		t.Fatalf(`expected LongestPrefix == "/", got %q`, key)
+	}
+
+	if _, _, ok = tree.LongestPrefix("api"); ok {
+		t.Fatalf(`expected LongestPrefix("api") not to be ok, but was`)
+	}
+}
+
+func TestWalkPrefix(t *testing.T) {
+	tree := New().
+		Insert("/usr/local/bin", 1).
+		Insert("/usr/lib", 2).
+		Insert("/usr", 3).
+		Insert("/etc", 4)
+
+	AssertWalk(t, tree, "/usr", []string{"/usr", "/usr/lib", "/usr/local/bin"})
+	AssertWalk(t, tree, "/usr/l", []string{"/usr/lib", "/usr/local/bin"})
+	AssertWalk(t, tree, "/usr/lo", []string{"/usr/local/bin"})
+	AssertWalk(t, tree, "/var", nil)
+	AssertWalk(t, tree, "", []string{"/etc", "/usr", "/usr/lib", "/usr/local/bin"})
+}
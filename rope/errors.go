@@ -0,0 +1,23 @@
+package rope
+
+import (
+	"fmt"
+)
+
+// Error type returned when using an offset beyond the end of the rope
+type OutOfBounds struct {
+	Offset uint32
+}
+
+func (e *OutOfBounds) Error() string {
+	return fmt.Sprintf("offset %d out of bounds", e.Offset)
+}
+
+// Error type returned when a byte offset falls inside a UTF-8 sequence
+type NotRuneBoundary struct {
+	Offset uint32
+}
+
+func (e *NotRuneBoundary) Error() string {
+	return fmt.Sprintf("offset %d is not on a rune boundary", e.Offset)
+}
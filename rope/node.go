@@ -0,0 +1,258 @@
+package rope
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// The maximum number of bytes stored in each leaf
+	CHUNK = 1 << 9
+)
+
+// Representation of a rope node.
+// Leaves hold a chunk of UTF-8 text, branches hold two children.
+// Every node caches the byte, rune and newline counts of its text, so
+// offsets and line numbers can be found by descending the tree.
+type Node struct {
+	// The left child of a branch
+	Left *Node
+	// The right child of a branch
+	Right *Node
+	// The text of a leaf
+	Chunk string
+	// The height of this subtree, where leaves have height 0
+	Height uint32
+	// The number of bytes in this subtree
+	Bytes uint32
+	// The number of runes in this subtree
+	Runes uint32
+	// The number of newlines in this subtree
+	Lines uint32
+}
+
+// Create a new leaf holding chunk.
+// Complexity: O(1)
+func newLeaf(chunk string) *Node {
+	return &Node{
+		Chunk: chunk,
+		Bytes: uint32(len(chunk)),
+		Runes: uint32(utf8.RuneCountInString(chunk)),
+		Lines: uint32(strings.Count(chunk, "\n")),
+	}
+}
+
+// Create a new branch from two children, summing their metadata.
+// Complexity: O(1)
+func newBranch(left, right *Node) *Node {
+	height := left.Height
+	if right.Height > height {
+		height = right.Height
+	}
+	return &Node{
+		Left:   left,
+		Right:  right,
+		Height: height + 1,
+		Bytes:  left.Bytes + right.Bytes,
+		Runes:  left.Runes + right.Runes,
+		Lines:  left.Lines + right.Lines,
+	}
+}
+
+// Return true if node is a leaf.
+// Complexity: O(1)
+func (node *Node) isLeaf() bool {
+	return node.Left == nil
+}
+
+// Build a balanced tree from s, split into chunks on rune boundaries.
+// Complexity: O(n)
+func build(s string) *Node {
+	if len(s) == 0 {
+		return nil
+	}
+
+	leaves := make([]*Node, 0, len(s)/CHUNK+1)
+	for len(s) > 0 {
+		end := len(s)
+		if end > CHUNK {
+			end = CHUNK
+			for end > 1 && !utf8.RuneStart(s[end]) {
+				end--
+			}
+		}
+		leaves = append(leaves, newLeaf(s[:end]))
+		s = s[end:]
+	}
+
+	return buildLeaves(leaves)
+}
+
+// Build a balanced tree from a non-empty list of leaves.
+// Complexity: O(n)
+func buildLeaves(leaves []*Node) *Node {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	half := len(leaves) / 2
+	return newBranch(buildLeaves(leaves[:half]), buildLeaves(leaves[half:]))
+}
+
+// Create a branch from two subtrees whose heights differ by at most 2,
+// rotating to restore balance.
+// Complexity: O(1)
+func balance(left, right *Node) *Node {
+	switch {
+	case left.Height > right.Height+1:
+		if left.Left.Height >= left.Right.Height {
+			return newBranch(left.Left, newBranch(left.Right, right))
+		}
+		return newBranch(
+			newBranch(left.Left, left.Right.Left),
+			newBranch(left.Right.Right, right),
+		)
+	case right.Height > left.Height+1:
+		if right.Right.Height >= right.Left.Height {
+			return newBranch(newBranch(left, right.Left), right.Right)
+		}
+		return newBranch(
+			newBranch(left, right.Left.Left),
+			newBranch(right.Left.Right, right.Right),
+		)
+	}
+	return newBranch(left, right)
+}
+
+// Concatenate two trees of any height, returning a balanced tree.
+// Adjacent small leaves are merged into a single chunk.
+// Complexity: O(log(n))
+func join(left, right *Node) *Node {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.isLeaf() && right.isLeaf() && left.Bytes+right.Bytes <= CHUNK:
+		return newLeaf(left.Chunk + right.Chunk)
+	case left.Height > right.Height+1:
+		return balance(left.Left, join(left.Right, right))
+	case right.Height > left.Height+1:
+		return balance(join(left, right.Left), right.Right)
+	}
+	return newBranch(left, right)
+}
+
+// Split a tree at byte offset, which must be on a rune boundary.
+// Complexity: O(log(n))
+func split(node *Node, offset uint32) (*Node, *Node) {
+	switch {
+	case node == nil:
+		return nil, nil
+	case offset == 0:
+		return nil, node
+	case offset >= node.Bytes:
+		return node, nil
+	case node.isLeaf():
+		return newLeaf(node.Chunk[:offset]), newLeaf(node.Chunk[offset:])
+	case offset < node.Left.Bytes:
+		l, r := split(node.Left, offset)
+		return l, join(r, node.Right)
+	case offset > node.Left.Bytes:
+		l, r := split(node.Right, offset-node.Left.Bytes)
+		return join(node.Left, l), r
+	}
+	return node.Left, node.Right
+}
+
+// Return the byte at offset, which must be < node.Bytes.
+// Complexity: O(log(n))
+func (node *Node) byteAt(offset uint32) byte {
+	for !node.isLeaf() {
+		if offset < node.Left.Bytes {
+			node = node.Left
+		} else {
+			offset -= node.Left.Bytes
+			node = node.Right
+		}
+	}
+	return node.Chunk[offset]
+}
+
+// Return the byte offset of the rune at runeOffset.
+// Complexity: O(log(n))
+func (node *Node) byteOffset(runeOffset uint32) uint32 {
+	var acc uint32
+	for !node.isLeaf() {
+		if runeOffset < node.Left.Runes {
+			node = node.Left
+		} else {
+			runeOffset -= node.Left.Runes
+			acc += node.Left.Bytes
+			node = node.Right
+		}
+	}
+
+	for i := range node.Chunk {
+		if runeOffset == 0 {
+			return acc + uint32(i)
+		}
+		runeOffset--
+	}
+	return acc + node.Bytes
+}
+
+// Return the number of runes and newlines before byte offset.
+// Complexity: O(log(n))
+func (node *Node) countBefore(offset uint32) (runes uint32, lines uint32) {
+	for !node.isLeaf() {
+		if offset < node.Left.Bytes {
+			node = node.Left
+		} else {
+			offset -= node.Left.Bytes
+			runes += node.Left.Runes
+			lines += node.Left.Lines
+			node = node.Right
+		}
+	}
+
+	chunk := node.Chunk[:offset]
+	return runes + uint32(utf8.RuneCountInString(chunk)),
+		lines + uint32(strings.Count(chunk, "\n"))
+}
+
+// Return the byte offset just after the nth newline (counting from 1).
+// Complexity: O(log(n))
+func (node *Node) afterNewline(n uint32) uint32 {
+	var acc uint32
+	for !node.isLeaf() {
+		if n <= node.Left.Lines {
+			node = node.Left
+		} else {
+			n -= node.Left.Lines
+			acc += node.Left.Bytes
+			node = node.Right
+		}
+	}
+
+	for i := 0; i < len(node.Chunk); i++ {
+		if node.Chunk[i] == '\n' {
+			n--
+			if n == 0 {
+				return acc + uint32(i) + 1
+			}
+		}
+	}
+	return acc + node.Bytes
+}
+
+// Visit each leaf chunk in order until fn returns false.
+// Complexity: O(n)
+func (node *Node) eachChunk(fn func(string) bool) bool {
+	if node == nil {
+		return true
+	}
+	if node.isLeaf() {
+		return fn(node.Chunk)
+	}
+	return node.Left.eachChunk(fn) && node.Right.eachChunk(fn)
+}
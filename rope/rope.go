@@ -0,0 +1,291 @@
+package rope
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Persistent rope of UTF-8 text.
+// The text is stored in chunks at the leaves of a balanced binary tree, so
+// edits copy only the path to the edited chunks.
+type Rope struct {
+	// The root node of the rope, nil when empty
+	Root *Node
+}
+
+// Return a new rope containing the text s.
+// Complexity: O(n)
+func New(s string) *Rope {
+	return &Rope{Root: build(s)}
+}
+
+// Return the number of bytes in the rope.
+// Complexity: O(1)
+func (rope *Rope) Len() uint32 {
+	if rope.Root == nil {
+		return 0
+	}
+	return rope.Root.Bytes
+}
+
+// Return the number of runes in the rope.
+// Complexity: O(1)
+func (rope *Rope) RuneLen() uint32 {
+	if rope.Root == nil {
+		return 0
+	}
+	return rope.Root.Runes
+}
+
+// Return the number of lines in the rope.
+// This is one more than the number of newlines.
+// Complexity: O(1)
+func (rope *Rope) LineCount() uint32 {
+	if rope.Root == nil {
+		return 1
+	}
+	return rope.Root.Lines + 1
+}
+
+// Check that offset is within the rope and on a rune boundary.
+// Complexity: O(log(n))
+func (rope *Rope) checkOffset(offset uint32) error {
+	if offset > rope.Len() {
+		return &OutOfBounds{offset}
+	}
+	if offset < rope.Len() && !utf8.RuneStart(rope.Root.byteAt(offset)) {
+		return &NotRuneBoundary{offset}
+	}
+	return nil
+}
+
+// Check that start and end are valid offsets, with start <= end.
+// Complexity: O(log(n))
+func (rope *Rope) checkRange(start, end uint32) error {
+	if err := rope.checkOffset(end); err != nil {
+		return err
+	}
+	if start > end {
+		return &OutOfBounds{start}
+	}
+	return rope.checkOffset(start)
+}
+
+// Return the byte offset of the rune at runeOffset.
+// Offsets beyond the number of runes are an OutOfBounds error.
+// Complexity: O(log(n))
+func (rope *Rope) ByteOffset(runeOffset uint32) (uint32, error) {
+	if runeOffset > rope.RuneLen() {
+		return 0, &OutOfBounds{runeOffset}
+	}
+	if rope.Root == nil {
+		return 0, nil
+	}
+	return rope.Root.byteOffset(runeOffset), nil
+}
+
+// Return the rune offset of the byte at offset.
+// Offsets beyond the end of the rope are an OutOfBounds error.
+// Complexity: O(log(n))
+func (rope *Rope) RuneOffset(offset uint32) (uint32, error) {
+	if err := rope.checkOffset(offset); err != nil {
+		return 0, err
+	}
+	if rope.Root == nil {
+		return 0, nil
+	}
+	runes, _ := rope.Root.countBefore(offset)
+	return runes, nil
+}
+
+// Return the byte offset at which line begins, counting lines from 0.
+// Lines beyond the last line are an OutOfBounds error.
+// Complexity: O(log(n))
+func (rope *Rope) LineStart(line uint32) (uint32, error) {
+	if line >= rope.LineCount() {
+		return 0, &OutOfBounds{line}
+	}
+	if line == 0 {
+		return 0, nil
+	}
+	return rope.Root.afterNewline(line), nil
+}
+
+// Return the line and rune column of the byte at offset, counting from 0.
+// Complexity: O(log(n))
+func (rope *Rope) Position(offset uint32) (line uint32, column uint32, err error) {
+	if err = rope.checkOffset(offset); err != nil || rope.Root == nil {
+		return
+	}
+
+	runes, line := rope.Root.countBefore(offset)
+	start, _ := rope.LineStart(line)
+	startRunes, _ := rope.Root.countBefore(start)
+
+	return line, runes - startRunes, nil
+}
+
+// Return the byte offset of the rune at line and column, counting from 0.
+// Positions beyond the end of the line are an OutOfBounds error.
+// Complexity: O(log(n))
+func (rope *Rope) Offset(line, column uint32) (uint32, error) {
+	start, err := rope.LineStart(line)
+	if err != nil {
+		return 0, err
+	}
+
+	end := rope.Len()
+	if line+1 < rope.LineCount() {
+		end, _ = rope.LineStart(line + 1)
+		end--
+	}
+
+	startRunes, _ := rope.RuneOffset(start)
+	endRunes, _ := rope.RuneOffset(end)
+	if startRunes+column > endRunes {
+		return 0, &OutOfBounds{column}
+	}
+
+	return rope.ByteOffset(startRunes + column)
+}
+
+// Insert s at byte offset.
+// A new rope is returned, sharing memory with the original.
+// Offsets beyond the end or inside a rune are an error.
+// Complexity: O(log(n) + len(s))
+func (rope *Rope) Insert(offset uint32, s string) (*Rope, error) {
+	if err := rope.checkOffset(offset); err != nil {
+		return nil, err
+	}
+
+	l, r := split(rope.Root, offset)
+	return &Rope{Root: join(join(l, build(s)), r)}, nil
+}
+
+// Insert s at rune offset.
+// A new rope is returned, sharing memory with the original.
+// Complexity: O(log(n) + len(s))
+func (rope *Rope) InsertAtRune(runeOffset uint32, s string) (*Rope, error) {
+	offset, err := rope.ByteOffset(runeOffset)
+	if err != nil {
+		return nil, err
+	}
+	return rope.Insert(offset, s)
+}
+
+// Delete the bytes from start up to but not including end.
+// A new rope is returned, sharing memory with the original.
+// Complexity: O(log(n))
+func (rope *Rope) Delete(start, end uint32) (*Rope, error) {
+	if err := rope.checkRange(start, end); err != nil {
+		return nil, err
+	}
+
+	l, rest := split(rope.Root, start)
+	_, r := split(rest, end-start)
+	return &Rope{Root: join(l, r)}, nil
+}
+
+// Delete the runes from start up to but not including end.
+// A new rope is returned, sharing memory with the original.
+// Complexity: O(log(n))
+func (rope *Rope) DeleteRunes(start, end uint32) (*Rope, error) {
+	if start > end {
+		return nil, &OutOfBounds{start}
+	}
+
+	byteStart, err := rope.ByteOffset(start)
+	if err != nil {
+		return nil, err
+	}
+	byteEnd, err := rope.ByteOffset(end)
+	if err != nil {
+		return nil, err
+	}
+	return rope.Delete(byteStart, byteEnd)
+}
+
+// Return the bytes from start up to but not including end.
+// A new rope is returned, sharing memory with the original.
+// Complexity: O(log(n))
+func (rope *Rope) Slice(start, end uint32) (*Rope, error) {
+	if err := rope.checkRange(start, end); err != nil {
+		return nil, err
+	}
+
+	_, rest := split(rope.Root, start)
+	mid, _ := split(rest, end-start)
+	return &Rope{Root: mid}, nil
+}
+
+// Return the text of this rope followed by the text of other.
+// A new rope is returned, sharing memory with both originals.
+// Complexity: O(log(n))
+func (rope *Rope) Concat(other *Rope) *Rope {
+	return &Rope{Root: join(rope.Root, other.Root)}
+}
+
+// Return the text of the rope as a string.
+// Complexity: O(n)
+func (rope *Rope) String() string {
+	var b strings.Builder
+	b.Grow(int(rope.Len()))
+	rope.Root.eachChunk(func(chunk string) bool {
+		b.WriteString(chunk)
+		return true
+	})
+	return b.String()
+}
+
+// Return a reader over the text of this version of the rope.
+// Complexity: O(1)
+func (rope *Rope) Reader() *Reader {
+	r := &Reader{}
+	if rope.Root != nil {
+		r.stack = []*Node{rope.Root}
+	}
+	return r
+}
+
+// Reader streaming the chunks of a rope.
+type Reader struct {
+	// Nodes still to be read, with the next node last
+	stack []*Node
+	// The unread part of the current chunk
+	chunk string
+}
+
+// Read up to len(p) bytes into p.
+// Implements io.Reader.
+func (r *Reader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.chunk) == 0 && !r.nextChunk() {
+			break
+		}
+		c := copy(p[n:], r.chunk)
+		r.chunk = r.chunk[c:]
+		n += c
+	}
+
+	if n == 0 && len(p) > 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// Advance to the next leaf, returning false at the end of the rope.
+func (r *Reader) nextChunk() bool {
+	for len(r.stack) > 0 {
+		node := r.stack[len(r.stack)-1]
+		r.stack = r.stack[:len(r.stack)-1]
+
+		if node.isLeaf() {
+			r.chunk = node.Chunk
+			return true
+		}
+		r.stack = append(r.stack, node.Right, node.Left)
+	}
+	return false
+}
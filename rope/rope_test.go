@@ -0,0 +1,195 @@
+package rope
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func AssertText(t *testing.T, rope *Rope, s string) {
+	if rope.String() != s {
+		t.Fatalf(`expected rope.String() == %q, got %q`, s, rope.String())
+	}
+	if rope.Len() != uint32(len(s)) {
+		t.Fatalf(`expected rope.Len() == %d, got %d`, len(s), rope.Len())
+	}
+}
+
+func AssertBalanced(t *testing.T, node *Node) {
+	if node == nil || node.isLeaf() {
+		return
+	}
+	l, r := int(node.Left.Height), int(node.Right.Height)
+	if l-r > 1 || r-l > 1 {
+		t.Fatalf(`expected balanced subtrees, got heights %d and %d`, l, r)
+	}
+	AssertBalanced(t, node.Left)
+	AssertBalanced(t, node.Right)
+}
+
+func TestNew(t *testing.T) {
+	s := strings.Repeat("héllo wörld\n", 500)
+	rope := New(s)
+
+	AssertText(t, rope, s)
+	AssertBalanced(t, rope.Root)
+
+	if rope.RuneLen() != 6000 {
+		t.Fatalf(`expected rope.RuneLen() == 6000, got %d`, rope.RuneLen())
+	}
+	if rope.LineCount() != 501 {
+		t.Fatalf(`expected rope.LineCount() == 501, got %d`, rope.LineCount())
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	rope := New("")
+
+	AssertText(t, rope, "")
+	if rope.LineCount() != 1 {
+		t.Fatalf(`expected rope.LineCount() == 1, got %d`, rope.LineCount())
+	}
+}
+
+func TestInsert(t *testing.T) {
+	rope := New("hello world")
+
+	cpy, err := rope.Insert(5, ",")
+	if err != nil {
+		t.Fatalf(`expected rope.Insert(5, ...) to be ok, got %s`, err)
+	}
+
+	AssertText(t, cpy, "hello, world")
+	AssertText(t, rope, "hello world")
+
+	if _, err = rope.Insert(12, "!"); err == nil {
+		t.Fatalf(`expected rope.Insert(12, ...) not to be ok, but was`)
+	}
+}
+
+func TestInsertManyStaysBalanced(t *testing.T) {
+	rope := New("")
+	expected := ""
+
+	for i := 0; i < 2000; i++ {
+		offset := uint32(i*7) % (rope.Len() + 1)
+		var err error
+		rope, err = rope.Insert(offset, "abc")
+		if err != nil {
+			t.Fatalf(`expected rope.Insert(%d, ...) to be ok, got %s`, offset, err)
+		}
+		expected = expected[:offset] + "abc" + expected[offset:]
+	}
+
+	AssertText(t, rope, expected)
+	AssertBalanced(t, rope.Root)
+}
+
+func TestInsertAtRune(t *testing.T) {
+	rope := New("añb")
+
+	cpy, err := rope.InsertAtRune(2, "X")
+	if err != nil {
+		t.Fatalf(`expected rope.InsertAtRune(2, ...) to be ok, got %s`, err)
+	}
+	AssertText(t, cpy, "añXb")
+
+	if _, err = rope.Insert(2, "X"); err == nil {
+		t.Fatalf(`expected rope.Insert(2, ...) inside a rune not to be ok, but was`)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	s := strings.Repeat("0123456789", 200)
+	rope := New(s)
+
+	cpy, err := rope.Delete(5, 1995)
+	if err != nil {
+		t.Fatalf(`expected rope.Delete(5, 1995) to be ok, got %s`, err)
+	}
+
+	AssertText(t, cpy, "0123456789")
+	AssertText(t, rope, s)
+
+	runes, err := New("añbñc").DeleteRunes(1, 4)
+	if err != nil {
+		t.Fatalf(`expected DeleteRunes(1, 4) to be ok, got %s`, err)
+	}
+	AssertText(t, runes, "ac")
+}
+
+func TestSliceAndConcat(t *testing.T) {
+	s := strings.Repeat("abcdefghij", 300)
+	rope := New(s)
+
+	left, err := rope.Slice(0, 1234)
+	if err != nil {
+		t.Fatalf(`expected rope.Slice(0, 1234) to be ok, got %s`, err)
+	}
+	right, err := rope.Slice(1234, rope.Len())
+	if err != nil {
+		t.Fatalf(`expected rope.Slice(1234, ...) to be ok, got %s`, err)
+	}
+
+	AssertText(t, left, s[:1234])
+	AssertText(t, right, s[1234:])
+	AssertText(t, left.Concat(right), s)
+
+	if _, err = rope.Slice(10, 5); err == nil {
+		t.Fatalf(`expected rope.Slice(10, 5) not to be ok, but was`)
+	}
+}
+
+func TestLinesAndColumns(t *testing.T) {
+	rope := New("first\nsécond\n\nlast")
+
+	line, column, err := rope.Position(10)
+	if err != nil {
+		t.Fatalf(`expected rope.Position(10) to be ok, got %s`, err)
+	}
+	if line != 1 || column != 3 {
+		t.Fatalf(`expected rope.Position(10) == (1, 3), got (%d, %d)`, line, column)
+	}
+
+	offset, err := rope.Offset(1, 3)
+	if err != nil {
+		t.Fatalf(`expected rope.Offset(1, 3) to be ok, got %s`, err)
+	}
+	if offset != 10 {
+		t.Fatalf(`expected rope.Offset(1, 3) == 10, got %d`, offset)
+	}
+
+	start, err := rope.LineStart(3)
+	if err != nil {
+		t.Fatalf(`expected rope.LineStart(3) to be ok, got %s`, err)
+	}
+	if start != 15 {
+		t.Fatalf(`expected rope.LineStart(3) == 15, got %d`, start)
+	}
+
+	if _, err = rope.Offset(2, 1); err == nil {
+		t.Fatalf(`expected rope.Offset(2, 1) not to be ok, but was`)
+	}
+	if _, err = rope.LineStart(4); err == nil {
+		t.Fatalf(`expected rope.LineStart(4) not to be ok, but was`)
+	}
+}
+
+func TestReader(t *testing.T) {
+	s := strings.Repeat("persistent ", 1000)
+	rope := New(s)
+	cpy, _ := rope.Insert(0, ">> ")
+
+	b, err := ioutil.ReadAll(rope.Reader())
+	if err != nil {
+		t.Fatalf(`expected ReadAll to be ok, got %s`, err)
+	}
+	if string(b) != s {
+		t.Fatalf(`expected the reader to produce the original text`)
+	}
+
+	b, _ = ioutil.ReadAll(cpy.Reader())
+	if string(b) != ">> "+s {
+		t.Fatalf(`expected the reader to produce the edited text`)
+	}
+}
@@ -0,0 +1,321 @@
+package rtree
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// The maximum number of entries or children in a node
+	MAX_ENTRIES = 16
+	// The minimum number of entries or children in a non-root node
+	MIN_ENTRIES = 6
+)
+
+// A value stored in the tree with its bounding box
+type Entry struct {
+	Box   Rect
+	Value Value
+}
+
+// Internal tree node.
+// Every leaf is at the same depth.
+type Node struct {
+	// The bounding box of everything beneath this node
+	Box Rect
+	// True if this node holds entries rather than children
+	Leaf bool
+	// The entries in a leaf
+	Entries []Entry
+	// The children of a branch
+	Children []*Node
+}
+
+// Create a new leaf holding entries, computing its bounding box.
+// Complexity: O(MAX_ENTRIES)
+func newLeaf(entries []Entry) *Node {
+	node := &Node{Leaf: true, Entries: entries}
+	for i, e := range entries {
+		if i == 0 {
+			node.Box = e.Box
+		} else {
+			node.Box = node.Box.Union(e.Box)
+		}
+	}
+	return node
+}
+
+// Create a new branch holding children, computing its bounding box.
+// Complexity: O(MAX_ENTRIES)
+func newBranch(children []*Node) *Node {
+	node := &Node{Children: children}
+	for i, c := range children {
+		if i == 0 {
+			node.Box = c.Box
+		} else {
+			node.Box = node.Box.Union(c.Box)
+		}
+	}
+	return node
+}
+
+// Return the number of entries or children in this node.
+// Complexity: O(1)
+func (node *Node) size() int {
+	if node.Leaf {
+		return len(node.Entries)
+	}
+	return len(node.Children)
+}
+
+// Return the bounding box of each entry or child in this node.
+// Complexity: O(MAX_ENTRIES)
+func (node *Node) boxes() []Rect {
+	boxes := make([]Rect, 0, node.size())
+	for _, e := range node.Entries {
+		boxes = append(boxes, e.Box)
+	}
+	for _, c := range node.Children {
+		boxes = append(boxes, c.Box)
+	}
+	return boxes
+}
+
+// Split an overfull node in two.
+// Complexity: O(MAX_ENTRIES^2)
+func (node *Node) split() (*Node, *Node) {
+	left, right := quadraticSplit(node.boxes())
+
+	if node.Leaf {
+		return newLeaf(pickEntries(node.Entries, left)), newLeaf(pickEntries(node.Entries, right))
+	}
+	return newBranch(pickChildren(node.Children, left)), newBranch(pickChildren(node.Children, right))
+}
+
+// Return the entries at the indices idx.
+// Complexity: O(len(idx))
+func pickEntries(entries []Entry, idx []int) []Entry {
+	acc := make([]Entry, 0, len(idx))
+	for _, i := range idx {
+		acc = append(acc, entries[i])
+	}
+	return acc
+}
+
+// Return the children at the indices idx.
+// Complexity: O(len(idx))
+func pickChildren(children []*Node, idx []int) []*Node {
+	acc := make([]*Node, 0, len(idx))
+	for _, i := range idx {
+		acc = append(acc, children[i])
+	}
+	return acc
+}
+
+// Partition boxes into two groups of at least MIN_ENTRIES, using Guttman's
+// quadratic split.
+// Complexity: O(n^2)
+func quadraticSplit(boxes []Rect) (left, right []int) {
+	s1, s2, worst := 0, 1, math.Inf(-1)
+	for i := range boxes {
+		for j := i + 1; j < len(boxes); j++ {
+			d := boxes[i].Union(boxes[j]).Area() - boxes[i].Area() - boxes[j].Area()
+			if d > worst {
+				s1, s2, worst = i, j, d
+			}
+		}
+	}
+
+	left, right = []int{s1}, []int{s2}
+	lbox, rbox := boxes[s1], boxes[s2]
+
+	rest := make([]int, 0, len(boxes)-2)
+	for i := range boxes {
+		if i != s1 && i != s2 {
+			rest = append(rest, i)
+		}
+	}
+
+	for len(rest) > 0 {
+		if len(left)+len(rest) == MIN_ENTRIES {
+			return append(left, rest...), right
+		}
+		if len(right)+len(rest) == MIN_ENTRIES {
+			return left, append(right, rest...)
+		}
+
+		// pick the box with the strongest preference for one group
+		next, diff := 0, math.Inf(-1)
+		for k, i := range rest {
+			d := math.Abs(lbox.Enlargement(boxes[i]) - rbox.Enlargement(boxes[i]))
+			if d > diff {
+				next, diff = k, d
+			}
+		}
+
+		i := rest[next]
+		rest = append(rest[:next], rest[next+1:]...)
+
+		dl, dr := lbox.Enlargement(boxes[i]), rbox.Enlargement(boxes[i])
+		toLeft := dl < dr ||
+			(dl == dr && lbox.Area() < rbox.Area()) ||
+			(dl == dr && lbox.Area() == rbox.Area() && len(left) <= len(right))
+
+		if toLeft {
+			left, lbox = append(left, i), lbox.Union(boxes[i])
+		} else {
+			right, rbox = append(right, i), rbox.Union(boxes[i])
+		}
+	}
+
+	return left, right
+}
+
+// Return the index of the child needing the least enlargement to cover box.
+// Complexity: O(MAX_ENTRIES)
+func (node *Node) chooseSubtree(box Rect) int {
+	best, bestGrowth, bestArea := 0, math.Inf(1), math.Inf(1)
+	for i, c := range node.Children {
+		growth, area := c.Box.Enlargement(box), c.Box.Area()
+		if growth < bestGrowth || (growth == bestGrowth && area < bestArea) {
+			best, bestGrowth, bestArea = i, growth, area
+		}
+	}
+	return best
+}
+
+// Insert e beneath node, copying the path to its leaf.
+// If the node overflows, it is split and the second half returned.
+// Complexity: O(log(n))
+func (node *Node) insert(e Entry) (*Node, *Node) {
+	var into *Node
+
+	if node.Leaf {
+		entries := make([]Entry, len(node.Entries), len(node.Entries)+1)
+		copy(entries, node.Entries)
+		into = newLeaf(append(entries, e))
+	} else {
+		i := node.chooseSubtree(e.Box)
+		child, sibling := node.Children[i].insert(e)
+
+		children := make([]*Node, len(node.Children), len(node.Children)+1)
+		copy(children, node.Children)
+		children[i] = child
+		if sibling != nil {
+			children = append(children, sibling)
+		}
+		into = newBranch(children)
+	}
+
+	if into.size() > MAX_ENTRIES {
+		return into.split()
+	}
+	return into, nil
+}
+
+// Remove the first entry with box whose value satisfies match from beneath
+// node, copying the path to it.
+// Children left with fewer than MIN_ENTRIES are removed, and the entries
+// beneath them returned to be inserted again.
+// Complexity: O(n) worst case, since boxes may overlap
+func (node *Node) remove(box Rect, match func(Value) bool) (into *Node, found bool, orphans []Entry) {
+	if node.Leaf {
+		for i, x := range node.Entries {
+			if x.Box == box && match(x.Value) {
+				entries := make([]Entry, 0, len(node.Entries)-1)
+				entries = append(append(entries, node.Entries[:i]...), node.Entries[i+1:]...)
+				return newLeaf(entries), true, nil
+			}
+		}
+		return node, false, nil
+	}
+
+	for i, c := range node.Children {
+		if !c.Box.Contains(box) {
+			continue
+		}
+
+		child, ok, orphans := c.remove(box, match)
+		if !ok {
+			continue
+		}
+
+		children := make([]*Node, 0, len(node.Children))
+		children = append(children, node.Children[:i]...)
+		if child.size() < MIN_ENTRIES {
+			orphans = child.collect(orphans)
+		} else {
+			children = append(children, child)
+		}
+		children = append(children, node.Children[i+1:]...)
+
+		return newBranch(children), true, orphans
+	}
+
+	return node, false, nil
+}
+
+// Append every entry beneath node to acc.
+// Complexity: O(n)
+func (node *Node) collect(acc []Entry) []Entry {
+	if node.Leaf {
+		return append(acc, node.Entries...)
+	}
+	for _, c := range node.Children {
+		acc = c.collect(acc)
+	}
+	return acc
+}
+
+// Call fn with each entry beneath node intersecting box, until fn returns
+// false.
+// Complexity: O(log(n) + k) for k results, when boxes rarely overlap
+func (node *Node) search(box Rect, fn func(Entry) bool) bool {
+	if node.Leaf {
+		for _, e := range node.Entries {
+			if e.Box.Intersects(box) && !fn(e) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, c := range node.Children {
+		if c.Box.Intersects(box) && !c.search(box, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Return the indices 0...len(boxes)-1 grouped into runs of MAX_ENTRIES,
+// tiled by Sort-Tile-Recursive: sorted into vertical slabs by center x,
+// then each slab sorted by center y.
+// Complexity: O(n*log(n))
+func tile(boxes []Rect) [][]int {
+	n := len(boxes)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.Slice(order, func(a, b int) bool {
+		return boxes[order[a]].Center().X < boxes[order[b]].Center().X
+	})
+
+	pages := (n + MAX_ENTRIES - 1) / MAX_ENTRIES
+	slabSize := int(math.Ceil(math.Sqrt(float64(pages)))) * MAX_ENTRIES
+
+	var groups [][]int
+	for s := 0; s < n; s += slabSize {
+		slab := order[s:min(s+slabSize, n)]
+		sort.Slice(slab, func(a, b int) bool {
+			return boxes[slab[a]].Center().Y < boxes[slab[b]].Center().Y
+		})
+		for g := 0; g < len(slab); g += MAX_ENTRIES {
+			groups = append(groups, slab[g:min(g+MAX_ENTRIES, len(slab))])
+		}
+	}
+
+	return groups
+}
@@ -0,0 +1,70 @@
+package rtree
+
+import (
+	"math"
+)
+
+// A point in the plane
+type Point struct {
+	X, Y float64
+}
+
+// An axis-aligned rectangle, including its edges
+type Rect struct {
+	Min, Max Point
+}
+
+// Return the rectangle covering only p.
+// Complexity: O(1)
+func (p Point) Rect() Rect {
+	return Rect{p, p}
+}
+
+// Return the area of r.
+// Complexity: O(1)
+func (r Rect) Area() float64 {
+	return (r.Max.X - r.Min.X) * (r.Max.Y - r.Min.Y)
+}
+
+// Return the smallest rectangle covering both r and other.
+// Complexity: O(1)
+func (r Rect) Union(other Rect) Rect {
+	return Rect{
+		Min: Point{math.Min(r.Min.X, other.Min.X), math.Min(r.Min.Y, other.Min.Y)},
+		Max: Point{math.Max(r.Max.X, other.Max.X), math.Max(r.Max.Y, other.Max.Y)},
+	}
+}
+
+// Return true if r and other share at least one point.
+// Complexity: O(1)
+func (r Rect) Intersects(other Rect) bool {
+	return r.Min.X <= other.Max.X && other.Min.X <= r.Max.X &&
+		r.Min.Y <= other.Max.Y && other.Min.Y <= r.Max.Y
+}
+
+// Return true if every point of other is inside r.
+// Complexity: O(1)
+func (r Rect) Contains(other Rect) bool {
+	return r.Min.X <= other.Min.X && other.Max.X <= r.Max.X &&
+		r.Min.Y <= other.Min.Y && other.Max.Y <= r.Max.Y
+}
+
+// Return the area r would gain by growing to cover other.
+// Complexity: O(1)
+func (r Rect) Enlargement(other Rect) float64 {
+	return r.Union(other).Area() - r.Area()
+}
+
+// Return the center of r.
+// Complexity: O(1)
+func (r Rect) Center() Point {
+	return Point{(r.Min.X + r.Max.X) / 2, (r.Min.Y + r.Max.Y) / 2}
+}
+
+// Return the squared distance from p to the nearest point of r.
+// Complexity: O(1)
+func (r Rect) Distance(p Point) float64 {
+	dx := math.Max(0, math.Max(r.Min.X-p.X, p.X-r.Max.X))
+	dy := math.Max(0, math.Max(r.Min.Y-p.Y, p.Y-r.Max.Y))
+	return dx*dx + dy*dy
+}
@@ -0,0 +1,197 @@
+package rtree
+
+import (
+	"container/heap"
+)
+
+// Values storable in the tree
+type Value = interface{}
+
+// Persistent R-tree indexing values by bounding box.
+// Updates copy the path from the root to the changed leaf, as with
+// vector.Node.Set, so every snapshot can be queried while others are built.
+type Tree struct {
+	// The root node of the tree
+	Root *Node
+	// The number of entries in the tree
+	Length uint32
+}
+
+// Value for the empty tree
+var empty = &Tree{Root: newLeaf(nil)}
+
+// Return a new empty tree.
+// Complexity: O(1)
+func New() *Tree {
+	return empty
+}
+
+// Return a new balanced tree containing entries..., packed by
+// Sort-Tile-Recursive bulk loading.
+// Complexity: O(n*log(n))
+func Load(entries ...Entry) *Tree {
+	if len(entries) == 0 {
+		return empty
+	}
+
+	boxes := make([]Rect, len(entries))
+	for i, e := range entries {
+		boxes[i] = e.Box
+	}
+
+	var nodes []*Node
+	for _, group := range tile(boxes) {
+		nodes = append(nodes, newLeaf(pickEntries(entries, group)))
+	}
+
+	for len(nodes) > 1 {
+		boxes = boxes[:0]
+		for _, n := range nodes {
+			boxes = append(boxes, n.Box)
+		}
+
+		var parents []*Node
+		for _, group := range tile(boxes) {
+			parents = append(parents, newBranch(pickChildren(nodes, group)))
+		}
+		nodes = parents
+	}
+
+	return &Tree{
+		Root:   nodes[0],
+		Length: uint32(len(entries)),
+	}
+}
+
+// Return the number of entries in this tree.
+// Complexity: O(1)
+func (t *Tree) Count() uint32 {
+	return t.Length
+}
+
+// Insert value into the tree with the bounding box box.
+// A new tree is returned, sharing memory with the original.
+// Complexity: O(log(n))
+func (t *Tree) Insert(box Rect, value Value) *Tree {
+	root, sibling := t.Root.insert(Entry{box, value})
+	if sibling != nil {
+		root = newBranch([]*Node{root, sibling})
+	}
+
+	return &Tree{
+		Root:   root,
+		Length: t.Length + 1,
+	}
+}
+
+// Delete the entry with bounding box box and value value.
+// Values are compared with ==, so deleting a value of an uncomparable type,
+// such as a slice or map, panics. Use DeleteFunc for those.
+// A new tree is returned, sharing memory with the original.
+// Attempting to delete an entry not in the tree returns itself.
+// Complexity: O(log(n)) when boxes rarely overlap
+func (t *Tree) Delete(box Rect, value Value) *Tree {
+	return t.DeleteFunc(box, func(v Value) bool {
+		return v == value
+	})
+}
+
+// Delete the first entry with bounding box box whose value satisfies match.
+// A new tree is returned, sharing memory with the original.
+// Attempting to delete an entry not in the tree returns itself.
+// Complexity: O(log(n)) when boxes rarely overlap
+func (t *Tree) DeleteFunc(box Rect, match func(Value) bool) *Tree {
+	root, found, orphans := t.Root.remove(box, match)
+	if !found {
+		return t
+	}
+
+	for !root.Leaf && len(root.Children) <= 1 {
+		if len(root.Children) == 0 {
+			root = empty.Root
+		} else {
+			root = root.Children[0]
+		}
+	}
+
+	for _, e := range orphans {
+		r, sibling := root.insert(e)
+		if sibling != nil {
+			r = newBranch([]*Node{r, sibling})
+		}
+		root = r
+	}
+
+	return &Tree{
+		Root:   root,
+		Length: t.Length - 1,
+	}
+}
+
+// Return every entry whose bounding box intersects box.
+// Complexity: O(log(n) + k) for k results, when boxes rarely overlap
+func (t *Tree) Search(box Rect) []Entry {
+	var acc []Entry
+	t.Root.search(box, func(e Entry) bool {
+		acc = append(acc, e)
+		return true
+	})
+	return acc
+}
+
+// Return up to k entries nearest to p, closest first.
+// Distance is measured from p to the nearest point of each bounding box.
+// Complexity: O(k*log(n)) when boxes rarely overlap
+func (t *Tree) Nearest(k int, p Point) []Entry {
+	var acc []Entry
+
+	queue := &nearQueue{{node: t.Root, dist: t.Root.Box.Distance(p)}}
+	for queue.Len() > 0 && len(acc) < k {
+		item := heap.Pop(queue).(nearItem)
+
+		if item.node == nil {
+			acc = append(acc, item.entry)
+			continue
+		}
+
+		for _, e := range item.node.Entries {
+			heap.Push(queue, nearItem{entry: e, dist: e.Box.Distance(p)})
+		}
+		for _, c := range item.node.Children {
+			heap.Push(queue, nearItem{node: c, dist: c.Box.Distance(p)})
+		}
+	}
+
+	return acc
+}
+
+// Call fn with each entry in the tree, until fn returns false.
+// Complexity: O(n)
+func (t *Tree) Each(fn func(Entry) bool) {
+	t.Root.search(t.Root.Box, fn)
+}
+
+// A node or entry queued during a nearest neighbor search
+type nearItem struct {
+	node  *Node
+	entry Entry
+	dist  float64
+}
+
+// Priority queue of nodes and entries by distance, for container/heap
+type nearQueue []nearItem
+
+func (q nearQueue) Len() int           { return len(q) }
+func (q nearQueue) Less(i, j int) bool { return q[i].dist < q[j].dist }
+func (q nearQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *nearQueue) Push(x interface{}) {
+	*q = append(*q, x.(nearItem))
+}
+
+func (q *nearQueue) Pop() interface{} {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,200 @@
+package rtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func RandomEntries(n int) []Entry {
+	r := rand.New(rand.NewSource(42))
+	entries := make([]Entry, n)
+	for i := range entries {
+		x, y := r.Float64()*1000, r.Float64()*1000
+		w, h := r.Float64()*20, r.Float64()*20
+		entries[i] = Entry{Rect{Point{x, y}, Point{x + w, y + h}}, i}
+	}
+	return entries
+}
+
+func Build(entries []Entry) *Tree {
+	t := New()
+	for _, e := range entries {
+		t = t.Insert(e.Box, e.Value)
+	}
+	return t
+}
+
+// Check every leaf is at the same depth and no node is overfull
+func AssertBalanced(t *testing.T, tree *Tree) {
+	depth := -1
+	var walk func(n *Node, d int)
+	walk = func(n *Node, d int) {
+		if n.size() > MAX_ENTRIES {
+			t.Fatalf(`expected at most %d entries per node, got %d`, MAX_ENTRIES, n.size())
+		}
+		if n.Leaf {
+			if depth >= 0 && d != depth {
+				t.Fatalf(`expected every leaf at depth %d, got %d`, depth, d)
+			}
+			depth = d
+		}
+		for _, c := range n.Children {
+			if !n.Box.Contains(c.Box) {
+				t.Fatalf(`expected %v to contain %v`, n.Box, c.Box)
+			}
+			walk(c, d+1)
+		}
+	}
+	walk(tree.Root, 0)
+}
+
+func AssertSearch(t *testing.T, tree *Tree, entries []Entry, box Rect) {
+	expected := map[Value]bool{}
+	for _, e := range entries {
+		if e.Box.Intersects(box) {
+			expected[e.Value] = true
+		}
+	}
+
+	found := tree.Search(box)
+	if len(found) != len(expected) {
+		t.Fatalf(`expected %d results for %v, got %d`, len(expected), box, len(found))
+	}
+	for _, e := range found {
+		if !expected[e.Value] {
+			t.Fatalf(`expected %v not to be in the results for %v`, e.Value, box)
+		}
+	}
+}
+
+var queries = []Rect{
+	{Point{0, 0}, Point{100, 100}},
+	{Point{450, 450}, Point{550, 700}},
+	{Point{-10, -10}, Point{-1, -1}},
+	{Point{0, 0}, Point{1000, 1000}},
+}
+
+func TestInsertAndSearch(t *testing.T) {
+	entries := RandomEntries(2000)
+	tree := Build(entries)
+
+	if tree.Count() != 2000 {
+		t.Fatalf(`expected tree.Count() == 2000, got %d`, tree.Count())
+	}
+
+	AssertBalanced(t, tree)
+	for _, q := range queries {
+		AssertSearch(t, tree, entries, q)
+	}
+}
+
+func TestInsertIsPersistent(t *testing.T) {
+	entries := RandomEntries(200)
+	tree := Build(entries[:100])
+	cpy := tree
+
+	for _, e := range entries[100:] {
+		cpy = cpy.Insert(e.Box, e.Value)
+	}
+
+	AssertSearch(t, tree, entries[:100], queries[3])
+	AssertSearch(t, cpy, entries, queries[3])
+}
+
+func TestDelete(t *testing.T) {
+	entries := RandomEntries(1000)
+	tree := Build(entries)
+	cpy := tree
+
+	for _, e := range entries[:700] {
+		cpy = cpy.Delete(e.Box, e.Value)
+	}
+
+	if cpy.Count() != 300 {
+		t.Fatalf(`expected cpy.Count() == 300, got %d`, cpy.Count())
+	}
+
+	AssertBalanced(t, cpy)
+	for _, q := range queries {
+		AssertSearch(t, cpy, entries[700:], q)
+		AssertSearch(t, tree, entries, q)
+	}
+
+	if cpy.Delete(entries[0].Box, entries[0].Value) != cpy {
+		t.Fatalf(`expected deleting a missing entry to return itself`)
+	}
+
+	for _, e := range entries[700:] {
+		cpy = cpy.Delete(e.Box, e.Value)
+	}
+	if cpy.Count() != 0 || len(cpy.Search(queries[3])) != 0 {
+		t.Fatalf(`expected deleting every entry to leave an empty tree`)
+	}
+}
+
+func TestDeleteFunc(t *testing.T) {
+	box := Rect{Point{0, 0}, Point{1, 1}}
+	tree := New().Insert(box, []int{1}).Insert(box, []int{2})
+
+	cpy := tree.DeleteFunc(box, func(v Value) bool {
+		return v.([]int)[0] == 2
+	})
+	if cpy.Count() != 1 {
+		t.Fatalf(`expected cpy.Count() == 1, got %d`, cpy.Count())
+	}
+	if found := cpy.Search(box); len(found) != 1 || found[0].Value.([]int)[0] != 1 {
+		t.Fatalf(`expected only []int{1} to remain, got %v`, found)
+	}
+
+	none := func(v Value) bool {
+		return false
+	}
+	if tree.DeleteFunc(box, none) != tree {
+		t.Fatalf(`expected deleting a missing entry to return itself`)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	entries := RandomEntries(5000)
+	tree := Load(entries...)
+
+	if tree.Count() != 5000 {
+		t.Fatalf(`expected tree.Count() == 5000, got %d`, tree.Count())
+	}
+
+	AssertBalanced(t, tree)
+	for _, q := range queries {
+		AssertSearch(t, tree, entries, q)
+	}
+
+	tree = tree.Insert(Rect{Point{-5, -5}, Point{-2, -2}}, "new")
+	if len(tree.Search(queries[2])) != 1 {
+		t.Fatalf(`expected to find the entry inserted after loading`)
+	}
+}
+
+func TestNearest(t *testing.T) {
+	entries := RandomEntries(1000)
+	tree := Load(entries...)
+	p := Point{500, 500}
+
+	sorted := append([]Entry{}, entries...)
+	sort.Slice(sorted, func(a, b int) bool {
+		return sorted[a].Box.Distance(p) < sorted[b].Box.Distance(p)
+	})
+
+	found := tree.Nearest(10, p)
+	if len(found) != 10 {
+		t.Fatalf(`expected 10 results, got %d`, len(found))
+	}
+	for i, e := range found {
+		if e.Box.Distance(p) != sorted[i].Box.Distance(p) {
+			t.Fatalf(`expected result %d at distance %f, got %f`, i, sorted[i].Box.Distance(p), e.Box.Distance(p))
+		}
+	}
+
+	if len(New().Nearest(3, p)) != 0 {
+		t.Fatalf(`expected no results from an empty tree`)
+	}
+}
@@ -0,0 +1,259 @@
+package persistent
+
+import (
+	"./vector"
+	"sync"
+)
+
+// Lazy, cached sequence of values.
+// Each cell is realized at most once, on first access, and shared by every
+// seq built from it. The nil *Seq is the empty sequence.
+type Seq struct {
+	// The function producing this cell's contents, nil once realized
+	fn func() *Seq
+	// Guards realization of fn
+	once sync.Once
+	// True if this cell realized to the empty sequence
+	empty bool
+	// The first value in the sequence
+	first Value
+	// The remaining values in the sequence
+	rest *Seq
+}
+
+// Return a new seq with first before the values of rest.
+// Complexity: O(1)
+func Cons(first Value, rest *Seq) *Seq {
+	return &Seq{first: first, rest: rest}
+}
+
+// Return a new seq whose values are those of the seq returned by fn.
+// fn is not called until the seq is first accessed.
+// Complexity: O(1)
+func Lazy(fn func() *Seq) *Seq {
+	return &Seq{fn: fn}
+}
+
+// Return a seq of the elements of vec, realized one leaf at a time.
+// Complexity: O(1)
+func FromVector(vec *vector.Vector) *Seq {
+	return vectorSeq(vec, 0)
+}
+
+// Return a seq of the elements of vec from key i onwards.
+// Complexity: O(1)
+func vectorSeq(vec *vector.Vector, i uint32) *Seq {
+	return Lazy(func() *Seq {
+		if i >= vec.Count() {
+			return nil
+		}
+
+		start := vec.Offset + i
+		leaf := vec.Root.Leaf(start)
+		end := i + vector.SIZE - (start & vector.MASK)
+		if end > vec.Count() {
+			end = vec.Count()
+		}
+
+		acc := vectorSeq(vec, end)
+		for j := end; j > i; j-- {
+			acc = Cons(leaf.Elements[(start+j-i-1)&vector.MASK], acc)
+		}
+		return acc
+	})
+}
+
+// Realize this cell, returning nil if the seq is empty.
+// Complexity: O(1) once realized
+func (s *Seq) realize() *Seq {
+	if s == nil {
+		return nil
+	}
+
+	s.once.Do(func() {
+		if s.fn == nil {
+			return
+		}
+
+		if r := s.fn().realize(); r != nil {
+			s.first, s.rest = r.first, r.rest
+		} else {
+			s.empty = true
+		}
+		s.fn = nil
+	})
+
+	if s.empty {
+		return nil
+	}
+	return s
+}
+
+// Return true if the seq contains no values.
+// Complexity: O(1) once realized
+func (s *Seq) IsEmpty() bool {
+	return s.realize() == nil
+}
+
+// Return the first value in the seq, or nil if empty.
+// Complexity: O(1) once realized
+func (s *Seq) First() Value {
+	if r := s.realize(); r != nil {
+		return r.first
+	}
+	return nil
+}
+
+// Return the seq without its first value, which may be empty.
+// The returned seq is not realized.
+// Complexity: O(1) once realized
+func (s *Seq) Rest() *Seq {
+	if r := s.realize(); r != nil {
+		return r.rest
+	}
+	return nil
+}
+
+// Return the seq without its first value, or nil if that would be empty.
+// Complexity: O(1) once realized
+func (s *Seq) Next() *Seq {
+	return s.Rest().realize()
+}
+
+// Call fn with each value in order, until fn returns false.
+// Complexity: O(n)
+func (s *Seq) Each(fn func(Value) bool) {
+	for r := s.realize(); r != nil; r = r.rest.realize() {
+		if !fn(r.first) {
+			return
+		}
+	}
+}
+
+// Return a lazy seq of fn applied to each value.
+// Complexity: O(1)
+func (s *Seq) Map(fn func(Value) Value) *Seq {
+	return Lazy(func() *Seq {
+		r := s.realize()
+		if r == nil {
+			return nil
+		}
+		return Cons(fn(r.first), r.rest.Map(fn))
+	})
+}
+
+// Return a lazy seq of the values for which pred holds.
+// Complexity: O(1)
+func (s *Seq) Filter(pred func(Value) bool) *Seq {
+	return Lazy(func() *Seq {
+		for r := s.realize(); r != nil; r = r.rest.realize() {
+			if pred(r.first) {
+				return Cons(r.first, r.rest.Filter(pred))
+			}
+		}
+		return nil
+	})
+}
+
+// Return a lazy seq of at most the first n values.
+// Complexity: O(1)
+func (s *Seq) Take(n uint32) *Seq {
+	return Lazy(func() *Seq {
+		if n == 0 {
+			return nil
+		}
+		r := s.realize()
+		if r == nil {
+			return nil
+		}
+		return Cons(r.first, r.rest.Take(n-1))
+	})
+}
+
+// Return a lazy seq of the values before the first for which pred fails.
+// Complexity: O(1)
+func (s *Seq) TakeWhile(pred func(Value) bool) *Seq {
+	return Lazy(func() *Seq {
+		r := s.realize()
+		if r == nil || !pred(r.first) {
+			return nil
+		}
+		return Cons(r.first, r.rest.TakeWhile(pred))
+	})
+}
+
+// Return a lazy seq of all but the first n values.
+// Complexity: O(1)
+func (s *Seq) Drop(n uint32) *Seq {
+	return Lazy(func() *Seq {
+		r := s.realize()
+		for ; n > 0 && r != nil; n-- {
+			r = r.rest.realize()
+		}
+		return r
+	})
+}
+
+// Return a lazy seq of the values of this seq followed by those of others.
+// Complexity: O(1)
+func (s *Seq) Concat(others ...*Seq) *Seq {
+	return Lazy(func() *Seq {
+		if r := s.realize(); r != nil {
+			return Cons(r.first, r.rest.Concat(others...))
+		}
+		if len(others) == 0 {
+			return nil
+		}
+		return others[0].Concat(others[1:]...)
+	})
+}
+
+// Return a lazy seq of the first value of this seq and each of others, then
+// the second, and so on, stopping when any seq is exhausted.
+// Complexity: O(1)
+func (s *Seq) Interleave(others ...*Seq) *Seq {
+	return Lazy(func() *Seq {
+		seqs := append([]*Seq{s}, others...)
+		for i, x := range seqs {
+			if seqs[i] = x.realize(); seqs[i] == nil {
+				return nil
+			}
+		}
+
+		acc := seqs[0].rest.Interleave(rests(seqs[1:])...)
+		for i := len(seqs); i > 0; i-- {
+			acc = Cons(seqs[i-1].first, acc)
+		}
+		return acc
+	})
+}
+
+// Return the rest of each realized seq in seqs.
+// Complexity: O(len(seqs))
+func rests(seqs []*Seq) []*Seq {
+	acc := make([]*Seq, len(seqs))
+	for i, x := range seqs {
+		acc[i] = x.rest
+	}
+	return acc
+}
+
+// Return a lazy seq of vectors of n consecutive values.
+// A final partition with fewer than n values is dropped.
+// Complexity: O(1)
+func (s *Seq) Partition(n uint32) *Seq {
+	return Lazy(func() *Seq {
+		if n == 0 {
+			return nil
+		}
+
+		part, r := vector.New(), s
+		for part.Count() < n {
+			if r = r.realize(); r == nil {
+				return nil
+			}
+			part, r = part.Append(r.first), r.rest
+		}
+		return Cons(part, r.Partition(n))
+	})
+}
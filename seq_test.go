@@ -0,0 +1,119 @@
+package persistent
+
+import (
+	"./vector"
+	"testing"
+)
+
+func AssertSeq(t *testing.T, s *Seq, elems ...Value) {
+	var found []Value
+	s.Each(func(v Value) bool {
+		found = append(found, v)
+		return true
+	})
+
+	if len(found) != len(elems) {
+		t.Fatalf(`expected %d values, got %d (%v)`, len(elems), len(found), found)
+	}
+	for i, v := range elems {
+		if found[i] != v {
+			t.Fatalf(`expected value %d == %v, got %v`, i, v, found[i])
+		}
+	}
+}
+
+// Return the infinite seq n, n+1, n+2...
+func Naturals(n int) *Seq {
+	return Lazy(func() *Seq {
+		return Cons(n, Naturals(n+1))
+	})
+}
+
+func TestFromVector(t *testing.T) {
+	vec := vector.New()
+	for i := 0; i < 100; i++ {
+		vec = vec.Append(i)
+	}
+	vec = vec.Drop(5).Prepend(-1)
+
+	var expected []Value
+	vec.Each(func(v Value) bool {
+		expected = append(expected, v)
+		return true
+	})
+
+	AssertSeq(t, FromVector(vec), expected...)
+	AssertSeq(t, FromVector(vector.New()))
+}
+
+func TestFirstRestNext(t *testing.T) {
+	s := FromVector(Vector(1, 2))
+
+	if s.First() != 1 || s.Rest().First() != 2 {
+		t.Fatalf(`expected s to start 1, 2`)
+	}
+	if s.Next().Next() != nil || s.Rest().Rest() == nil {
+		t.Fatalf(`expected Next() to be nil and Rest() to be an empty seq at the end`)
+	}
+	if !s.Rest().Rest().IsEmpty() {
+		t.Fatalf(`expected the rest of the last value to be empty`)
+	}
+
+	var empty *Seq
+	if empty.First() != nil || !empty.IsEmpty() || empty.Next() != nil {
+		t.Fatalf(`expected the nil seq to be empty`)
+	}
+}
+
+func TestRealizedOnce(t *testing.T) {
+	calls := 0
+	s := Naturals(0).Map(func(v Value) Value {
+		calls++
+		return v.(int) * 2
+	})
+
+	AssertSeq(t, s.Take(3), 0, 2, 4)
+	AssertSeq(t, s.Take(3), 0, 2, 4)
+
+	if calls != 3 {
+		t.Fatalf(`expected fn to be called 3 times, got %d`, calls)
+	}
+}
+
+func TestFilterAndTakeWhile(t *testing.T) {
+	even := Naturals(0).Filter(func(v Value) bool {
+		return v.(int)%2 == 0
+	})
+	small := even.TakeWhile(func(v Value) bool {
+		return v.(int) < 10
+	})
+
+	AssertSeq(t, small, 0, 2, 4, 6, 8)
+}
+
+func TestDropAndConcat(t *testing.T) {
+	s := Naturals(0).Drop(3).Take(2).Concat(nil, FromVector(Vector("a", "b")))
+
+	AssertSeq(t, s, 3, 4, "a", "b")
+	AssertSeq(t, FromVector(Vector(1)).Drop(5))
+}
+
+func TestInterleave(t *testing.T) {
+	s := Naturals(0).Interleave(FromVector(Vector("a", "b", "c")), Naturals(100))
+
+	AssertSeq(t, s, 0, "a", 100, 1, "b", 101, 2, "c", 102)
+}
+
+func TestPartition(t *testing.T) {
+	var found []Value
+	Naturals(0).Take(7).Partition(3).Each(func(v Value) bool {
+		part := v.(*vector.Vector)
+		x, _ := part.Get(0)
+		found = append(found, part.Count(), x)
+		return true
+	})
+
+	if len(found) != 4 || found[0] != uint32(3) || found[3] != 3 {
+		t.Fatalf(`expected 2 partitions of 3 starting 0 and 3, got %v`, found)
+	}
+}
@@ -0,0 +1,14 @@
+package sortedset
+
+import (
+	"fmt"
+)
+
+// Error type returned when selecting an invalid index
+type OutOfBounds struct {
+	Index uint32
+}
+
+func (e *OutOfBounds) Error() string {
+	return fmt.Sprintf("index %d out of bounds", e.Index)
+}
@@ -0,0 +1,162 @@
+package sortedset
+
+// Representation of an AVL tree node annotated with its subtree size.
+type Node struct {
+	// The element stored at this node
+	Value Value
+	// Elements less than Value
+	Left *Node
+	// Elements greater than Value
+	Right *Node
+	// The height of this subtree
+	Height uint32
+	// The number of elements in this subtree
+	Size uint32
+}
+
+// Return the height of node, where nil has height 0.
+// Complexity: O(1)
+func (node *Node) height() uint32 {
+	if node == nil {
+		return 0
+	}
+	return node.Height
+}
+
+// Return the size of node, where nil has size 0.
+// Complexity: O(1)
+func (node *Node) size() uint32 {
+	if node == nil {
+		return 0
+	}
+	return node.Size
+}
+
+// Create a new node, computing its height and size.
+// Complexity: O(1)
+func newNode(value Value, left, right *Node) *Node {
+	height := left.height()
+	if right.height() > height {
+		height = right.height()
+	}
+	return &Node{
+		Value:  value,
+		Left:   left,
+		Right:  right,
+		Height: height + 1,
+		Size:   left.size() + right.size() + 1,
+	}
+}
+
+// Create a new node, rotating if the subtrees differ in height by 2.
+// Complexity: O(1)
+func balance(value Value, left, right *Node) *Node {
+	switch {
+	case left.height() > right.height()+1:
+		if left.Left.height() >= left.Right.height() {
+			return newNode(left.Value, left.Left, newNode(value, left.Right, right))
+		}
+		return newNode(
+			left.Right.Value,
+			newNode(left.Value, left.Left, left.Right.Left),
+			newNode(value, left.Right.Right, right),
+		)
+	case right.height() > left.height()+1:
+		if right.Right.height() >= right.Left.height() {
+			return newNode(right.Value, newNode(value, left, right.Left), right.Right)
+		}
+		return newNode(
+			right.Left.Value,
+			newNode(value, left, right.Left.Left),
+			newNode(right.Value, right.Left.Right, right.Right),
+		)
+	}
+	return newNode(value, left, right)
+}
+
+// Insert value beneath node, returning the new root.
+// Returns node itself if value is already present.
+// Complexity: O(log(n))
+func insert(cmp Comparator, node *Node, value Value) *Node {
+	if node == nil {
+		return newNode(value, nil, nil)
+	}
+
+	c := cmp(value, node.Value)
+	switch {
+	case c < 0:
+		left := insert(cmp, node.Left, value)
+		if left == node.Left {
+			return node
+		}
+		return balance(node.Value, left, node.Right)
+	case c > 0:
+		right := insert(cmp, node.Right, value)
+		if right == node.Right {
+			return node
+		}
+		return balance(node.Value, node.Left, right)
+	}
+	return node
+}
+
+// Remove value from beneath node, returning the new root.
+// Returns node itself if value is not present.
+// Complexity: O(log(n))
+func remove(cmp Comparator, node *Node, value Value) *Node {
+	if node == nil {
+		return nil
+	}
+
+	c := cmp(value, node.Value)
+	switch {
+	case c < 0:
+		left := remove(cmp, node.Left, value)
+		if left == node.Left {
+			return node
+		}
+		return balance(node.Value, left, node.Right)
+	case c > 0:
+		right := remove(cmp, node.Right, value)
+		if right == node.Right {
+			return node
+		}
+		return balance(node.Value, node.Left, right)
+	}
+
+	if node.Right == nil {
+		return node.Left
+	}
+	min, right := removeMin(node.Right)
+	return balance(min, node.Left, right)
+}
+
+// Remove the smallest element beneath node, returning it and the new root.
+// Complexity: O(log(n))
+func removeMin(node *Node) (Value, *Node) {
+	if node.Left == nil {
+		return node.Value, node.Right
+	}
+	min, left := removeMin(node.Left)
+	return min, balance(node.Value, left, node.Right)
+}
+
+// Visit each element beneath node in order, from index start, until fn
+// returns false.
+// Complexity: O(log(n) + k)
+func each(node *Node, start uint32, fn func(Value) bool) bool {
+	for node != nil {
+		left := node.Left.size()
+		if start > left {
+			start -= left + 1
+			node = node.Right
+			continue
+		}
+		if !each(node.Left, start, fn) || !fn(node.Value) {
+			return false
+		}
+		start = 0
+		node = node.Right
+	}
+	return true
+}
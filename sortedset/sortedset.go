@@ -0,0 +1,169 @@
+package sortedset
+
+import (
+	"../vector"
+)
+
+// Values storable in the set
+type Value = interface{}
+
+// Function ordering two values.
+// Returns < 0 if a < b, 0 if a == b, and > 0 if a > b.
+type Comparator func(a, b Value) int
+
+// Persistent sorted set supporting order statistics.
+// Each node of the underlying AVL tree carries the size of its subtree, so
+// positions can be found without visiting every element.
+type SortedSet struct {
+	// The ordering of elements in the set
+	Compare Comparator
+	// The root node of the tree
+	Root *Node
+}
+
+// Return a new set ordered by cmp containing elements...
+// Complexity: O(n*log(n))
+func New(cmp Comparator, elements ...Value) *SortedSet {
+	set := &SortedSet{Compare: cmp}
+	for _, v := range elements {
+		set = set.Add(v)
+	}
+	return set
+}
+
+// Return the number of elements in this set.
+// Complexity: O(1)
+func (set *SortedSet) Count() uint32 {
+	return set.Root.size()
+}
+
+// Return true if value is in the set.
+// Complexity: O(log(n))
+func (set *SortedSet) Contains(value Value) bool {
+	node := set.Root
+	for node != nil {
+		c := set.Compare(value, node.Value)
+		switch {
+		case c < 0:
+			node = node.Left
+		case c > 0:
+			node = node.Right
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// Add a value to the set.
+// A new set is returned, sharing memory with the original.
+// Adding a value already in the set returns itself.
+// Complexity: O(log(n))
+func (set *SortedSet) Add(value Value) *SortedSet {
+	root := insert(set.Compare, set.Root, value)
+	if root == set.Root {
+		return set
+	}
+	return &SortedSet{Compare: set.Compare, Root: root}
+}
+
+// Remove a value from the set.
+// A new set is returned, sharing memory with the original.
+// Removing a value not in the set returns itself.
+// Complexity: O(log(n))
+func (set *SortedSet) Remove(value Value) *SortedSet {
+	root := remove(set.Compare, set.Root, value)
+	if root == set.Root {
+		return set
+	}
+	return &SortedSet{Compare: set.Compare, Root: root}
+}
+
+// Return the number of elements less than value.
+// If value is in the set, this is its index.
+// Complexity: O(log(n))
+func (set *SortedSet) Rank(value Value) uint32 {
+	var rank uint32
+
+	node := set.Root
+	for node != nil {
+		c := set.Compare(value, node.Value)
+		switch {
+		case c < 0:
+			node = node.Left
+		case c > 0:
+			rank += node.Left.size() + 1
+			node = node.Right
+		default:
+			return rank + node.Left.size()
+		}
+	}
+
+	return rank
+}
+
+// Get the element at index i in sorted order.
+// Selecting an index that is not in the set is an OutOfBounds error.
+// Complexity: O(log(n))
+func (set *SortedSet) Select(i uint32) (Value, error) {
+	if i >= set.Count() {
+		return nil, &OutOfBounds{i}
+	}
+
+	node := set.Root
+	for {
+		left := node.Left.size()
+		switch {
+		case i < left:
+			node = node.Left
+		case i > left:
+			i -= left + 1
+			node = node.Right
+		default:
+			return node.Value, nil
+		}
+	}
+}
+
+// Return the number of elements x where from <= x < to.
+// Complexity: O(log(n))
+func (set *SortedSet) CountRange(from, to Value) uint32 {
+	lo, hi := set.Rank(from), set.Rank(to)
+	if hi < lo {
+		return 0
+	}
+	return hi - lo
+}
+
+// Return a vector of the elements with indices start <= i < end.
+// The range is clamped to the size of the set.
+// Complexity: O(log(n) + k)
+func (set *SortedSet) Slice(start, end uint32) *vector.Vector {
+	if end > set.Count() {
+		end = set.Count()
+	}
+
+	vec := vector.New()
+	if start >= end {
+		return vec
+	}
+
+	each(set.Root, start, func(v Value) bool {
+		vec = vec.Append(v)
+		return vec.Count() < end-start
+	})
+
+	return vec
+}
+
+// Return a vector of every element in sorted order.
+// Complexity: O(n)
+func (set *SortedSet) ToVector() *vector.Vector {
+	return set.Slice(0, set.Count())
+}
+
+// Call fn with each element in sorted order, until fn returns false.
+// Complexity: O(n)
+func (set *SortedSet) Each(fn func(Value) bool) {
+	each(set.Root, 0, fn)
+}
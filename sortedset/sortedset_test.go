@@ -0,0 +1,132 @@
+package sortedset
+
+import (
+	"../vector"
+	"testing"
+)
+
+func CompareInts(a, b Value) int {
+	return a.(int) - b.(int)
+}
+
+func AssertVector(t *testing.T, vec *vector.Vector, elems []Value) {
+	if vec.Count() != uint32(len(elems)) {
+		t.Fatalf(`expected vec.Count() == %d, got %d`, len(elems), vec.Count())
+	}
+	for i, v := range elems {
+		x, err := vec.Get(uint32(i))
+		if err != nil {
+			t.Fatalf(`expected vec.Get(%d) to be ok, got %s`, i, err)
+		}
+		if x != v {
+			t.Fatalf(`expected vec.Get(%d) == %v, got %v`, i, v, x)
+		}
+	}
+}
+
+func AssertBalanced(t *testing.T, node *Node) {
+	if node == nil {
+		return
+	}
+	l, r := int(node.Left.height()), int(node.Right.height())
+	if l-r > 1 || r-l > 1 {
+		t.Fatalf(`expected balanced subtrees, got heights %d and %d`, l, r)
+	}
+	if node.Size != node.Left.size()+node.Right.size()+1 {
+		t.Fatalf(`expected node.Size to match its subtrees, got %d`, node.Size)
+	}
+	AssertBalanced(t, node.Left)
+	AssertBalanced(t, node.Right)
+}
+
+func TestAdd(t *testing.T) {
+	set := New(CompareInts, 5, 1, 4, 1, 3)
+	cpy := set.Add(2)
+
+	AssertVector(t, set.ToVector(), []Value{1, 3, 4, 5})
+	AssertVector(t, cpy.ToVector(), []Value{1, 2, 3, 4, 5})
+
+	if set.Add(4) != set {
+		t.Fatalf(`expected adding an existing value to return itself`)
+	}
+	if !cpy.Contains(2) || set.Contains(2) {
+		t.Fatalf(`expected only cpy to contain 2`)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	set := New(CompareInts)
+	for i := 0; i < 1000; i++ {
+		set = set.Add((i * 7919) % 1000)
+	}
+	AssertBalanced(t, set.Root)
+
+	cpy := set
+	for i := 0; i < 1000; i += 2 {
+		cpy = cpy.Remove(i)
+	}
+	AssertBalanced(t, cpy.Root)
+
+	if cpy.Count() != 500 {
+		t.Fatalf(`expected cpy.Count() == 500, got %d`, cpy.Count())
+	}
+	if set.Count() != 1000 {
+		t.Fatalf(`expected set.Count() == 1000, got %d`, set.Count())
+	}
+	if cpy.Remove(2) != cpy {
+		t.Fatalf(`expected removing a missing value to return itself`)
+	}
+}
+
+func TestRankAndSelect(t *testing.T) {
+	set := New(CompareInts)
+	for i := 0; i < 300; i++ {
+		set = set.Add(i * 10)
+	}
+
+	for i := 0; i < 300; i++ {
+		if rank := set.Rank(i * 10); rank != uint32(i) {
+			t.Fatalf(`expected set.Rank(%d) == %d, got %d`, i*10, i, rank)
+		}
+		if rank := set.Rank(i*10 + 5); rank != uint32(i+1) {
+			t.Fatalf(`expected set.Rank(%d) == %d, got %d`, i*10+5, i+1, rank)
+		}
+
+		x, err := set.Select(uint32(i))
+		if err != nil {
+			t.Fatalf(`expected set.Select(%d) to be ok, got %s`, i, err)
+		}
+		if x != i*10 {
+			t.Fatalf(`expected set.Select(%d) == %d, got %v`, i, i*10, x)
+		}
+	}
+
+	if _, err := set.Select(300); err == nil {
+		t.Fatalf(`expected set.Select(300) not to be ok, but was`)
+	}
+}
+
+func TestCountRange(t *testing.T) {
+	set := New(CompareInts, 1, 3, 5, 7, 9, 11)
+
+	if n := set.CountRange(3, 9); n != 3 {
+		t.Fatalf(`expected set.CountRange(3, 9) == 3, got %d`, n)
+	}
+	if n := set.CountRange(0, 100); n != 6 {
+		t.Fatalf(`expected set.CountRange(0, 100) == 6, got %d`, n)
+	}
+	if n := set.CountRange(9, 3); n != 0 {
+		t.Fatalf(`expected set.CountRange(9, 3) == 0, got %d`, n)
+	}
+}
+
+func TestSlice(t *testing.T) {
+	set := New(CompareInts)
+	for i := 99; i >= 0; i-- {
+		set = set.Add(i)
+	}
+
+	AssertVector(t, set.Slice(10, 14), []Value{10, 11, 12, 13})
+	AssertVector(t, set.Slice(97, 200), []Value{97, 98, 99})
+	AssertVector(t, set.Slice(50, 50), []Value{})
+}
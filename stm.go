@@ -0,0 +1,288 @@
+package persistent
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The number of committed versions kept by each Ref for snapshot reads
+const REF_HISTORY = 10
+
+// The commit point of the most recent transaction
+var clock atomic.Uint64
+
+// The id of the most recently created Ref, giving a lock order
+var refIds atomic.Uint64
+
+// Transactional reference to a persistent value.
+// Refs are changed only inside Dosync, and every transaction reads a
+// consistent snapshot of all refs as of the moment it started.
+type Ref struct {
+	// Unique id, ordering locks during commit
+	id uint64
+	// Guards history
+	mu sync.Mutex
+	// Committed versions, newest first
+	history []refVersion
+}
+
+// A committed value and the commit point it was written at
+type refVersion struct {
+	value Value
+	point uint64
+}
+
+// Return a new ref holding value.
+// Complexity: O(1)
+func NewRef(value Value) *Ref {
+	return &Ref{
+		id:      refIds.Add(1),
+		history: []refVersion{{value, clock.Load()}},
+	}
+}
+
+// Return the most recently committed value, outside of any transaction.
+// Complexity: O(1)
+func (r *Ref) Deref() Value {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.history[0].value
+}
+
+// Return the newest value committed at or before point.
+// Returns false if that version is no longer kept.
+// Complexity: O(REF_HISTORY)
+func (r *Ref) at(point uint64) (Value, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, v := range r.history {
+		if v.point <= point {
+			return v.value, true
+		}
+	}
+	return nil, false
+}
+
+// Return the commit point of the newest version.
+// Complexity: O(1)
+func (r *Ref) latest() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.history[0].point
+}
+
+// Record value as committed at point.
+// Must be called while holding r.mu.
+// Complexity: O(REF_HISTORY)
+func (r *Ref) commit(value Value, point uint64) {
+	n := len(r.history) + 1
+	if n > REF_HISTORY {
+		n = REF_HISTORY
+	}
+
+	history := make([]refVersion, 0, n)
+	history = append(history, refVersion{value, point})
+	r.history = append(history, r.history[:n-1]...)
+}
+
+// Options controlling how a transaction retries
+type TxOptions struct {
+	// The maximum number of attempts, or 0 for no limit
+	MaxRetries int
+	// The longest time to keep retrying, or 0 for no limit
+	Timeout time.Duration
+}
+
+// Options used by Dosync
+var DefaultTxOptions = TxOptions{MaxRetries: 10000}
+
+// A running transaction, valid only inside the function given to Dosync
+type Tx struct {
+	// The commit point of the snapshot this transaction reads
+	readPoint uint64
+	// The in-transaction value of each ref read or written
+	values map[*Ref]Value
+	// The refs set by Set or Alter
+	sets map[*Ref]bool
+	// The refs protected by Ensure
+	ensures map[*Ref]bool
+	// The functions to apply again at commit, for refs changed by Commute
+	commutes map[*Ref][]func(Value) Value
+}
+
+// Signal, raised as a panic, that the transaction must retry
+type retrySignal struct{}
+
+// Abandon this attempt and retry the transaction.
+func (tx *Tx) retry() {
+	panic(retrySignal{})
+}
+
+// Return the value of ref in this transaction.
+// Complexity: O(REF_HISTORY)
+func (tx *Tx) Deref(ref *Ref) Value {
+	if v, ok := tx.values[ref]; ok {
+		return v
+	}
+
+	v, ok := ref.at(tx.readPoint)
+	if !ok {
+		tx.retry()
+	}
+	tx.values[ref] = v
+	return v
+}
+
+// Set the value of ref in this transaction.
+// If ref has been committed by another transaction since this one started,
+// the transaction retries.
+// Complexity: O(1)
+func (tx *Tx) Set(ref *Ref, value Value) {
+	if _, ok := tx.commutes[ref]; ok {
+		panic("persistent: Set of a ref after Commute in the same transaction")
+	}
+	if ref.latest() > tx.readPoint {
+		tx.retry()
+	}
+
+	tx.sets[ref] = true
+	tx.values[ref] = value
+}
+
+// Set the value of ref to fn applied to its value in this transaction, and
+// return the new value.
+// Complexity: O(REF_HISTORY), plus fn
+func (tx *Tx) Alter(ref *Ref, fn func(Value) Value) Value {
+	v := fn(tx.Deref(ref))
+	tx.Set(ref, v)
+	return v
+}
+
+// Set the value of ref to fn applied to its value, and return the new value.
+// At commit, fn is applied again to the latest committed value instead of
+// conflicting, so fn must be commutative with other updates to ref.
+// Complexity: O(REF_HISTORY), plus fn
+func (tx *Tx) Commute(ref *Ref, fn func(Value) Value) Value {
+	v := fn(tx.Deref(ref))
+	tx.values[ref] = v
+	if !tx.sets[ref] {
+		tx.commutes[ref] = append(tx.commutes[ref], fn)
+	}
+	return v
+}
+
+// Protect ref from changes by other transactions until this one commits,
+// without writing it, and return its value.
+// Complexity: O(REF_HISTORY)
+func (tx *Tx) Ensure(ref *Ref) Value {
+	v := tx.Deref(ref)
+	tx.ensures[ref] = true
+	return v
+}
+
+// Commit this transaction, returning false if it conflicts.
+// Complexity: O(n*log(n)) for n refs
+func (tx *Tx) commit() bool {
+	var refs []*Ref
+	for ref := range tx.sets {
+		refs = append(refs, ref)
+	}
+	for ref := range tx.ensures {
+		if !tx.sets[ref] {
+			refs = append(refs, ref)
+		}
+	}
+	for ref := range tx.commutes {
+		if !tx.sets[ref] && !tx.ensures[ref] {
+			refs = append(refs, ref)
+		}
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].id < refs[j].id
+	})
+	for _, ref := range refs {
+		ref.mu.Lock()
+		defer ref.mu.Unlock()
+	}
+
+	for _, ref := range refs {
+		if (tx.sets[ref] || tx.ensures[ref]) && ref.history[0].point > tx.readPoint {
+			return false
+		}
+	}
+
+	point := clock.Add(1)
+	for _, ref := range refs {
+		if fns, ok := tx.commutes[ref]; ok && !tx.sets[ref] {
+			v := ref.history[0].value
+			for _, fn := range fns {
+				v = fn(v)
+			}
+			ref.commit(v, point)
+		} else if tx.sets[ref] {
+			ref.commit(tx.values[ref], point)
+		}
+	}
+
+	return true
+}
+
+// Run fn in a transaction using DefaultTxOptions.
+// See DosyncWith.
+func Dosync(fn func(*Tx) error) error {
+	return DosyncWith(DefaultTxOptions, fn)
+}
+
+// Run fn in a transaction, committing every change it makes to refs
+// atomically.
+// fn is retried from the start when it conflicts with another transaction,
+// so must be free of side effects other than through tx. If fn returns an
+// error, nothing is committed and the error is returned. Exceeding the
+// retry limit is a RetryLimit error, and the timeout a TxTimeout error.
+func DosyncWith(opts TxOptions, fn func(*Tx) error) error {
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		tx := &Tx{
+			readPoint: clock.Load(),
+			values:    make(map[*Ref]Value),
+			sets:      make(map[*Ref]bool),
+			ensures:   make(map[*Ref]bool),
+			commutes:  make(map[*Ref][]func(Value) Value),
+		}
+
+		done, err := tx.run(fn)
+		if done {
+			return err
+		}
+
+		if opts.MaxRetries > 0 && attempt >= opts.MaxRetries {
+			return &RetryLimit{attempt}
+		}
+		if opts.Timeout > 0 && time.Since(start) >= opts.Timeout {
+			return &TxTimeout{attempt}
+		}
+		runtime.Gosched()
+	}
+}
+
+// Run fn and commit, returning false if the transaction must retry.
+func (tx *Tx) run(fn func(*Tx) error) (done bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(retrySignal); !ok {
+				panic(r)
+			}
+			done, err = false, nil
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return true, err
+	}
+	return tx.commit(), nil
+}
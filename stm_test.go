@@ -0,0 +1,163 @@
+package persistent
+
+import (
+	"./vector"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Move the first element of from onto the end of to
+func move(tx *Tx, from, to *Ref) {
+	src := tx.Deref(from).(*vector.Vector)
+	if src.Count() == 0 {
+		return
+	}
+	x, _ := src.Get(0)
+	tx.Set(from, src.Shift())
+	tx.Alter(to, func(v Value) Value {
+		return v.(*vector.Vector).Append(x)
+	})
+}
+
+func TestDosyncMovesAtomically(t *testing.T) {
+	a := NewRef(Vector())
+	b := NewRef(Vector())
+	Dosync(func(tx *Tx) error {
+		vec := vector.New()
+		for i := 0; i < 200; i++ {
+			vec = vec.Append(i)
+		}
+		tx.Set(a, vec)
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				err := Dosync(func(tx *Tx) error {
+					if i%2 == 0 {
+						move(tx, a, b)
+					} else {
+						move(tx, b, a)
+					}
+					return nil
+				})
+				if err != nil {
+					t.Errorf(`expected Dosync() to be ok, got %s`, err)
+				}
+			}
+		}(i)
+	}
+
+	// every snapshot sees all 200 elements split between a and b
+	done := make(chan bool)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			Dosync(func(tx *Tx) error {
+				n := tx.Deref(a).(*vector.Vector).Count() + tx.Deref(b).(*vector.Vector).Count()
+				if n != 200 {
+					t.Errorf(`expected 200 elements in a snapshot, got %d`, n)
+				}
+				return nil
+			})
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+
+	total := a.Deref().(*vector.Vector).Count() + b.Deref().(*vector.Vector).Count()
+	if total != 200 {
+		t.Fatalf(`expected 200 elements, got %d`, total)
+	}
+}
+
+func TestCommute(t *testing.T) {
+	counter := NewRef(0)
+	inc := func(v Value) Value { return v.(int) + 1 }
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				Dosync(func(tx *Tx) error {
+					tx.Commute(counter, inc)
+					return nil
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if counter.Deref() != 1000 {
+		t.Fatalf(`expected counter == 1000, got %v`, counter.Deref())
+	}
+}
+
+func TestDosyncErrorAborts(t *testing.T) {
+	r := NewRef(1)
+	failed := errors.New("failed")
+
+	err := Dosync(func(tx *Tx) error {
+		tx.Set(r, 2)
+		return failed
+	})
+
+	if err != failed || r.Deref() != 1 {
+		t.Fatalf(`expected the error to be returned and nothing committed`)
+	}
+}
+
+// Commit a change to r from a separate transaction, forcing a conflict
+func interfere(r *Ref) {
+	go Dosync(func(tx *Tx) error {
+		tx.Alter(r, func(v Value) Value { return v.(int) + 1 })
+		return nil
+	})
+	for start := r.latest(); r.latest() == start; {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRetryLimit(t *testing.T) {
+	r := NewRef(0)
+	attempts := 0
+
+	err := DosyncWith(TxOptions{MaxRetries: 3}, func(tx *Tx) error {
+		attempts++
+		tx.Ensure(r)
+		interfere(r)
+		return nil
+	})
+
+	if _, ok := err.(*RetryLimit); !ok || attempts != 3 {
+		t.Fatalf(`expected a RetryLimit error after 3 attempts, got %v after %d`, err, attempts)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	r := NewRef(0)
+
+	err := DosyncWith(TxOptions{Timeout: 20 * time.Millisecond}, func(tx *Tx) error {
+		tx.Deref(r)
+		interfere(r)
+		tx.Set(r, -1)
+		return nil
+	})
+
+	if _, ok := err.(*TxTimeout); !ok {
+		t.Fatalf(`expected a TxTimeout error, got %v`, err)
+	}
+}
@@ -0,0 +1,35 @@
+package store
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// Converts the values stored in vector leaves to and from bytes
+type Codec interface {
+	// Return the encoding of v
+	Encode(v interface{}) ([]byte, error)
+	// Return the value encoded in b
+	Decode(b []byte) (interface{}, error)
+}
+
+// Codec using encoding/gob.
+// Concrete types other than Go's builtin types must be registered with
+// gob.Register.
+type GobCodec struct{}
+
+func (GobCodec) Encode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Decode(b []byte) (interface{}, error) {
+	var v interface{}
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
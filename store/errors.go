@@ -0,0 +1,15 @@
+package store
+
+import (
+	"fmt"
+)
+
+// Error type returned when a record in the store fails its checksum
+type Corrupt struct {
+	// The offset of the damaged record, or of the root pointer
+	Offset uint64
+}
+
+func (e *Corrupt) Error() string {
+	return fmt.Sprintf("corrupt record at offset %d", e.Offset)
+}
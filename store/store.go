@@ -0,0 +1,308 @@
+package store
+
+import (
+	"../vector"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"weak"
+)
+
+const (
+	// The name of the append-only file of nodes in a store directory
+	NODES_FILE = "nodes"
+	// The name of the file pointing to the committed root
+	ROOT_FILE = "root"
+)
+
+// Tags for the elements of a stored node
+const (
+	tagNull byte = iota
+	tagChild
+	tagValue
+)
+
+// Durable storage for vectors, in a directory.
+// Every vector.Node is written once to an append-only file and referenced by
+// its offset, so committing a new version writes only the nodes copied since
+// the last commit. Vectors read back load each branch on first visit.
+type Store struct {
+	// Guards the fields below
+	mu sync.Mutex
+	// The directory holding the store's files
+	dir string
+	// The append-only file of nodes
+	file *os.File
+	// The offset at which the next node is written
+	end uint64
+	// The codec for leaf values
+	codec Codec
+	// The offset of every node written or loaded that is still reachable.
+	// Entries are weak, so replaced versions can be garbage collected.
+	offsets map[weak.Pointer[vector.Node]]uint64
+}
+
+// Reference to a stored node, loaded on first visit
+type ref struct {
+	store  *Store
+	offset uint64
+	once   sync.Once
+	node   *vector.Node
+}
+
+// Load the referenced node.
+// A node that cannot be read causes a panic, since vector reads cannot fail.
+func (r *ref) Load() *vector.Node {
+	r.once.Do(func() {
+		node, err := r.store.readNode(r.offset)
+		if err != nil {
+			panic(err)
+		}
+		r.node = node
+	})
+	return r.node
+}
+
+// Open the store in dir, creating it if it does not exist.
+// Complexity: O(1)
+func Open(dir string, codec Codec) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(filepath.Join(dir, NODES_FILE), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &Store{
+		dir:     dir,
+		file:    file,
+		end:     uint64(info.Size()),
+		codec:   codec,
+		offsets: make(map[weak.Pointer[vector.Node]]uint64),
+	}, nil
+}
+
+// Close the store's files.
+// Vectors read from the store must not visit unloaded branches afterwards.
+func (s *Store) Close() error {
+	return s.file.Close()
+}
+
+// Return the most recently committed vector, or the empty vector if nothing
+// has been committed. Only the root node is read until other branches are
+// visited.
+// Complexity: O(1)
+func (s *Store) Head() (*vector.Vector, error) {
+	b, err := os.ReadFile(filepath.Join(s.dir, ROOT_FILE))
+	if os.IsNotExist(err) {
+		return vector.New(), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if len(b) != 20 || crc32.ChecksumIEEE(b[:16]) != binary.LittleEndian.Uint32(b[16:]) {
+		return nil, &Corrupt{0}
+	}
+
+	root, err := s.readNode(binary.LittleEndian.Uint64(b[0:]))
+	if err != nil {
+		return nil, err
+	}
+
+	return &vector.Vector{
+		Root:   root,
+		Length: binary.LittleEndian.Uint32(b[8:]),
+		Offset: binary.LittleEndian.Uint32(b[12:]),
+	}, nil
+}
+
+// Write every node of vec not already in the store, then durably point the
+// store's root at vec.
+// The nodes are synced before the root pointer is replaced, so a crash
+// leaves either the old or the new root committed.
+// Complexity: O(k) for the k nodes copied since the last commit
+func (s *Store) Commit(vec *vector.Vector) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	offset, err := s.writeNode(vec.Root)
+	if err != nil {
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+
+	b := make([]byte, 20)
+	binary.LittleEndian.PutUint64(b[0:], offset)
+	binary.LittleEndian.PutUint32(b[8:], vec.Length)
+	binary.LittleEndian.PutUint32(b[12:], vec.Offset)
+	binary.LittleEndian.PutUint32(b[16:], crc32.ChecksumIEEE(b[:16]))
+
+	return s.writeRoot(b)
+}
+
+// Atomically replace the root pointer file with b.
+func (s *Store) writeRoot(b []byte) error {
+	tmp := filepath.Join(s.dir, ROOT_FILE+".tmp")
+
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, filepath.Join(s.dir, ROOT_FILE)); err != nil {
+		return err
+	}
+
+	dir, err := os.Open(s.dir)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
+// Write node and any of its descendants not already stored, children first,
+// returning the offset of node.
+// Must be called while holding s.mu.
+// Complexity: O(k) for the k nodes written
+func (s *Store) writeNode(node *vector.Node) (uint64, error) {
+	if offset, ok := s.offsets[weak.Make(node)]; ok {
+		return offset, nil
+	}
+
+	buf := make([]byte, 8, 8+len(node.Elements)*9)
+	binary.LittleEndian.PutUint32(buf[4:], node.Shift)
+
+	for i, e := range node.Elements {
+		switch {
+		case e == vector.Null:
+			buf = append(buf, tagNull)
+		case node.Shift > 0:
+			var (
+				offset uint64
+				err    error
+			)
+			if r, ok := e.(*ref); ok && r.store == s {
+				offset = r.offset
+			} else if offset, err = s.writeNode(node.Child(uint32(i))); err != nil {
+				return 0, err
+			}
+			buf = append(buf, tagChild)
+			buf = binary.LittleEndian.AppendUint64(buf, offset)
+		default:
+			v, err := s.codec.Encode(e)
+			if err != nil {
+				return 0, err
+			}
+			buf = append(buf, tagValue)
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v)))
+			buf = append(buf, v...)
+		}
+	}
+
+	binary.LittleEndian.PutUint32(buf[0:], uint32(len(buf)-4))
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf[4:]))
+
+	offset := s.end
+	if _, err := s.file.WriteAt(buf, int64(offset)); err != nil {
+		return 0, err
+	}
+
+	s.end += uint64(len(buf))
+	s.remember(node, offset)
+	return offset, nil
+}
+
+// Read the node at offset, referencing its children lazily.
+// Complexity: O(1)
+func (s *Store) readNode(offset uint64) (*vector.Node, error) {
+	head := make([]byte, 4)
+	if _, err := s.file.ReadAt(head, int64(offset)); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, binary.LittleEndian.Uint32(head)+4)
+	if _, err := s.file.ReadAt(buf, int64(offset)+4); err != nil {
+		if err == io.EOF {
+			return nil, &Corrupt{offset}
+		}
+		return nil, err
+	}
+
+	body, sum := buf[:len(buf)-4], binary.LittleEndian.Uint32(buf[len(buf)-4:])
+	if crc32.ChecksumIEEE(body) != sum {
+		return nil, &Corrupt{offset}
+	}
+
+	node := &vector.Node{Shift: binary.LittleEndian.Uint32(body)}
+	body = body[4:]
+
+	for len(body) > 0 {
+		tag := body[0]
+		body = body[1:]
+
+		switch tag {
+		case tagNull:
+			node.Elements = append(node.Elements, vector.Null)
+		case tagChild:
+			child := &ref{store: s, offset: binary.LittleEndian.Uint64(body)}
+			node.Elements = append(node.Elements, child)
+			body = body[8:]
+		case tagValue:
+			n := binary.LittleEndian.Uint32(body)
+			v, err := s.codec.Decode(body[4 : 4+n])
+			if err != nil {
+				return nil, err
+			}
+			node.Elements = append(node.Elements, v)
+			body = body[4+n:]
+		default:
+			return nil, &Corrupt{offset}
+		}
+	}
+
+	s.mu.Lock()
+	s.remember(node, offset)
+	s.mu.Unlock()
+
+	return node, nil
+}
+
+// Record the offset of node until node is garbage collected.
+// Must be called while holding s.mu.
+// Complexity: O(1)
+func (s *Store) remember(node *vector.Node, offset uint64) {
+	key := weak.Make(node)
+	s.offsets[key] = offset
+
+	runtime.AddCleanup(node, func(key weak.Pointer[vector.Node]) {
+		s.mu.Lock()
+		delete(s.offsets, key)
+		s.mu.Unlock()
+	}, key)
+}
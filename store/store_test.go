@@ -0,0 +1,175 @@
+package store
+
+import (
+	"../vector"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"weak"
+)
+
+func Range(n int) *vector.Vector {
+	vec := vector.New()
+	for i := 0; i < n; i++ {
+		vec = vec.Append(i)
+	}
+	return vec
+}
+
+func AssertRange(t *testing.T, vec *vector.Vector, n int) {
+	if vec.Count() != uint32(n) {
+		t.Fatalf(`expected %d elements, got %d`, n, vec.Count())
+	}
+	for i := 0; i < n; i++ {
+		if x, _ := vec.Get(uint32(i)); x != i {
+			t.Fatalf(`expected vec.Get(%d) == %d, got %v`, i, i, x)
+		}
+	}
+}
+
+func Size(t *testing.T, dir string) int64 {
+	info, err := os.Stat(filepath.Join(dir, NODES_FILE))
+	if err != nil {
+		t.Fatalf(`expected the nodes file to exist, got %s`, err)
+	}
+	return info.Size()
+}
+
+func TestEmptyStore(t *testing.T) {
+	s, err := Open(t.TempDir(), GobCodec{})
+	if err != nil {
+		t.Fatalf(`expected Open() to be ok, got %s`, err)
+	}
+	defer s.Close()
+
+	vec, err := s.Head()
+	if err != nil || vec.Count() != 0 {
+		t.Fatalf(`expected an empty head, got %v, %s`, vec, err)
+	}
+}
+
+func TestCommitAndReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	s, _ := Open(dir, GobCodec{})
+	if err := s.Commit(Range(2000)); err != nil {
+		t.Fatalf(`expected s.Commit() to be ok, got %s`, err)
+	}
+	s.Close()
+
+	s, _ = Open(dir, GobCodec{})
+	defer s.Close()
+
+	vec, err := s.Head()
+	if err != nil {
+		t.Fatalf(`expected s.Head() to be ok, got %s`, err)
+	}
+	AssertRange(t, vec, 2000)
+}
+
+func TestCommitWritesOnlyCopiedPath(t *testing.T) {
+	dir := t.TempDir()
+	s, _ := Open(dir, GobCodec{})
+	s.Commit(Range(2000))
+	s.Close()
+
+	s, _ = Open(dir, GobCodec{})
+
+	vec, _ := s.Head()
+	before := Size(t, dir)
+
+	vec, _ = vec.Set(1500, "x")
+	vec = vec.Append(2000)
+	if err := s.Commit(vec); err != nil {
+		t.Fatalf(`expected s.Commit() to be ok, got %s`, err)
+	}
+
+	// the root, two branches and two leaves, at most ~600 bytes each
+	if grown := Size(t, dir) - before; grown > 5*600 {
+		t.Fatalf(`expected only the copied path to be written, grew by %d bytes`, grown)
+	}
+
+	loaded := 0
+	for _, e := range vec.Root.Elements {
+		if r, ok := e.(*ref); ok && r.node != nil {
+			loaded++
+		}
+	}
+	if loaded != 0 {
+		t.Fatalf(`expected unvisited branches to stay unloaded, %d were loaded`, loaded)
+	}
+
+	s.Close()
+
+	s, _ = Open(dir, GobCodec{})
+	defer s.Close()
+
+	head, _ := s.Head()
+	if x, _ := head.Get(1500); x != "x" {
+		t.Fatalf(`expected head.Get(1500) == "x", got %v`, x)
+	}
+	if x, _ := head.Get(2000); x != 2000 {
+		t.Fatalf(`expected head.Get(2000) == 2000, got %v`, x)
+	}
+	if x, _ := head.Get(3); x != 3 {
+		t.Fatalf(`expected head.Get(3) == 3, got %v`, x)
+	}
+}
+
+func TestReplacedVersionsAreCollected(t *testing.T) {
+	s, _ := Open(t.TempDir(), GobCodec{})
+	defer s.Close()
+
+	vec := Range(2000)
+	s.Commit(vec)
+	old := weak.Make(vec.Root)
+
+	vec, _ = vec.Set(1500, "x")
+	s.Commit(vec)
+
+	for i := 0; i < 10 && old.Value() != nil; i++ {
+		runtime.GC()
+	}
+	if old.Value() != nil {
+		t.Fatalf(`expected the replaced root to be garbage collected`)
+	}
+
+	vec, _ = vec.Set(3, "y")
+	if err := s.Commit(vec); err != nil {
+		t.Fatalf(`expected s.Commit() to be ok, got %s`, err)
+	}
+	if x, _ := vec.Get(1500); x != "x" {
+		t.Fatalf(`expected vec.Get(1500) == "x", got %v`, x)
+	}
+}
+
+func TestUncommittedNodesAreIgnored(t *testing.T) {
+	dir := t.TempDir()
+	s, _ := Open(dir, GobCodec{})
+	s.Commit(Range(10))
+
+	// simulate a crash after writing nodes, before replacing the root
+	s.mu.Lock()
+	s.writeNode(Range(50).Root)
+	s.mu.Unlock()
+	s.Close()
+
+	s, _ = Open(dir, GobCodec{})
+	defer s.Close()
+
+	vec, _ := s.Head()
+	AssertRange(t, vec, 10)
+}
+
+func TestCorruptRoot(t *testing.T) {
+	dir := t.TempDir()
+	s, _ := Open(dir, GobCodec{})
+	defer s.Close()
+	s.Commit(Range(10))
+
+	os.WriteFile(filepath.Join(dir, ROOT_FILE), []byte("garbage"), 0644)
+	if _, err := s.Head(); err == nil {
+		t.Fatalf(`expected s.Head() not to be ok, but was`)
+	}
+}
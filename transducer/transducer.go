@@ -0,0 +1,216 @@
+package transducer
+
+import (
+	"reflect"
+)
+
+// Values passed through a transformation
+type Value = interface{}
+
+// A reducing function, folding values into an accumulated result.
+type Reducer interface {
+	// Fold v into acc, returning the new result and false to stop early
+	Step(acc, v Value) (Value, bool)
+	// Finish the reduction, flushing any buffered values into acc
+	Complete(acc Value) Value
+}
+
+// A transformation of one reducing function into another.
+// Any state is created when the transducer is applied, so one transducer
+// can be used for many reductions.
+type Transducer func(Reducer) Reducer
+
+// Collections whose values can be reduced
+type Source interface {
+	// Call fn with each value in order, until fn returns false
+	Each(fn func(Value) bool)
+}
+
+// Source reading values from a slice
+type Slice []Value
+
+func (s Slice) Each(fn func(Value) bool) {
+	for _, v := range s {
+		if !fn(v) {
+			return
+		}
+	}
+}
+
+// Reducer built from a step function, with nothing to complete
+type ReducerFunc func(acc, v Value) (Value, bool)
+
+func (fn ReducerFunc) Step(acc, v Value) (Value, bool) {
+	return fn(acc, v)
+}
+
+func (fn ReducerFunc) Complete(acc Value) Value {
+	return acc
+}
+
+// Reducer with a custom step and completion
+type reducer struct {
+	step     func(acc, v Value) (Value, bool)
+	complete func(acc Value) Value
+}
+
+func (r *reducer) Step(acc, v Value) (Value, bool) {
+	return r.step(acc, v)
+}
+
+func (r *reducer) Complete(acc Value) Value {
+	return r.complete(acc)
+}
+
+// Return a transducer applying each of xforms in turn, first to last.
+// Complexity: O(1)
+func Comp(xforms ...Transducer) Transducer {
+	return func(rf Reducer) Reducer {
+		for i := len(xforms); i > 0; i-- {
+			rf = xforms[i-1](rf)
+		}
+		return rf
+	}
+}
+
+// Reduce source through xform into rf, starting from init.
+// A nil xform passes values through unchanged.
+// Complexity: O(n)
+func Reduce(xform Transducer, rf Reducer, init Value, source Source) Value {
+	if xform != nil {
+		rf = xform(rf)
+	}
+
+	acc := init
+	source.Each(func(v Value) bool {
+		var ok bool
+		acc, ok = rf.Step(acc, v)
+		return ok
+	})
+	return rf.Complete(acc)
+}
+
+// Return a transducer applying fn to each value.
+func Map(fn func(Value) Value) Transducer {
+	return func(rf Reducer) Reducer {
+		return &reducer{
+			step: func(acc, v Value) (Value, bool) {
+				return rf.Step(acc, fn(v))
+			},
+			complete: rf.Complete,
+		}
+	}
+}
+
+// Return a transducer keeping only the values for which pred holds.
+func Filter(pred func(Value) bool) Transducer {
+	return func(rf Reducer) Reducer {
+		return &reducer{
+			step: func(acc, v Value) (Value, bool) {
+				if !pred(v) {
+					return acc, true
+				}
+				return rf.Step(acc, v)
+			},
+			complete: rf.Complete,
+		}
+	}
+}
+
+// Return a transducer keeping at most the first n values, then stopping.
+func Take(n uint32) Transducer {
+	return func(rf Reducer) Reducer {
+		taken := uint32(0)
+		return &reducer{
+			step: func(acc, v Value) (Value, bool) {
+				if taken >= n {
+					return acc, false
+				}
+
+				taken++
+				acc, ok := rf.Step(acc, v)
+				return acc, ok && taken < n
+			},
+			complete: rf.Complete,
+		}
+	}
+}
+
+// Return a transducer dropping values equal (==) to the value before them.
+// Values of uncomparable types, such as Slices, are never dropped.
+func Dedupe() Transducer {
+	return func(rf Reducer) Reducer {
+		var (
+			prev Value
+			seen bool
+		)
+		return &reducer{
+			step: func(acc, v Value) (Value, bool) {
+				if seen && equal(prev, v) {
+					return acc, true
+				}
+				prev, seen = v, true
+				return rf.Step(acc, v)
+			},
+			complete: rf.Complete,
+		}
+	}
+}
+
+// Return true if a == b, or false if they cannot be compared.
+func equal(a, b Value) bool {
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) || (t != nil && !t.Comparable()) {
+		return false
+	}
+	return a == b
+}
+
+// Return a transducer grouping values into Slices of n.
+// The final Slice may hold fewer than n values.
+// A size of 0 causes a panic.
+func PartitionAll(n uint32) Transducer {
+	if n == 0 {
+		panic("transducer.PartitionAll requires n > 0")
+	}
+
+	return func(rf Reducer) Reducer {
+		var buf Slice
+		return &reducer{
+			step: func(acc, v Value) (Value, bool) {
+				buf = append(buf, v)
+				if uint32(len(buf)) < n {
+					return acc, true
+				}
+				part := buf
+				buf = nil
+				return rf.Step(acc, part)
+			},
+			complete: func(acc Value) Value {
+				if len(buf) > 0 {
+					part := buf
+					buf = nil
+					acc, _ = rf.Step(acc, part)
+				}
+				return rf.Complete(acc)
+			},
+		}
+	}
+}
+
+// Return a transducer passing on each value of every Source it receives.
+func Cat() Transducer {
+	return func(rf Reducer) Reducer {
+		return &reducer{
+			step: func(acc, v Value) (Value, bool) {
+				ok := true
+				v.(Source).Each(func(x Value) bool {
+					acc, ok = rf.Step(acc, x)
+					return ok
+				})
+				return acc, ok
+			},
+			complete: rf.Complete,
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package transducer
+
+import (
+	"testing"
+)
+
+// Reducer collecting values into a slice
+var appending = ReducerFunc(func(acc, v Value) (Value, bool) {
+	return append(acc.(Slice), v), true
+})
+
+func AssertReduce(t *testing.T, xform Transducer, source Slice, elems ...Value) {
+	found := Reduce(xform, appending, Slice(nil), source).(Slice)
+
+	if len(found) != len(elems) {
+		t.Fatalf(`expected %d values, got %d (%v)`, len(elems), len(found), found)
+	}
+	for i, v := range elems {
+		if p, ok := v.(Slice); ok {
+			if len(p) != len(found[i].(Slice)) {
+				t.Fatalf(`expected value %d == %v, got %v`, i, v, found[i])
+			}
+			continue
+		}
+		if found[i] != v {
+			t.Fatalf(`expected value %d == %v, got %v`, i, v, found[i])
+		}
+	}
+}
+
+func double(v Value) Value {
+	return v.(int) * 2
+}
+
+func odd(v Value) bool {
+	return v.(int)%2 == 1
+}
+
+func TestMapAndFilter(t *testing.T) {
+	AssertReduce(t, Map(double), Slice{1, 2, 3}, 2, 4, 6)
+	AssertReduce(t, Filter(odd), Slice{1, 2, 3}, 1, 3)
+	AssertReduce(t, nil, Slice{1, 2}, 1, 2)
+}
+
+func TestComp(t *testing.T) {
+	AssertReduce(t, Comp(Filter(odd), Map(double)), Slice{1, 2, 3, 4, 5}, 2, 6, 10)
+	AssertReduce(t, Comp(Map(double), Filter(odd)), Slice{1, 2, 3, 4, 5})
+}
+
+func TestTake(t *testing.T) {
+	seen := 0
+	counting := Map(func(v Value) Value {
+		seen++
+		return v
+	})
+
+	AssertReduce(t, Comp(counting, Take(2)), Slice{1, 2, 3, 4}, 1, 2)
+	if seen != 2 {
+		t.Fatalf(`expected Take(2) to stop after 2 values, saw %d`, seen)
+	}
+
+	AssertReduce(t, Take(0), Slice{1, 2})
+	AssertReduce(t, Take(5), Slice{1, 2}, 1, 2)
+}
+
+func TestDedupe(t *testing.T) {
+	AssertReduce(t, Dedupe(), Slice{1, 1, 2, 1, 1, 1, 3}, 1, 2, 1, 3)
+	AssertReduce(t, Dedupe(), Slice{nil, nil, 1, "1"}, nil, 1, "1")
+	AssertReduce(t, Comp(PartitionAll(2), Dedupe()), Slice{1, 1, 1, 1}, Slice{1, 1}, Slice{1, 1})
+}
+
+func TestPartitionAll(t *testing.T) {
+	xform := PartitionAll(2)
+
+	AssertReduce(t, xform, Slice{1, 2, 3, 4, 5}, Slice{1, 2}, Slice{3, 4}, Slice{5})
+	AssertReduce(t, xform, Slice{1, 2}, Slice{1, 2})
+	AssertReduce(t, Comp(Take(3), xform), Slice{1, 2, 3, 4, 5}, Slice{1, 2}, Slice{3})
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf(`expected PartitionAll(0) to panic, but did not`)
+		}
+	}()
+	PartitionAll(0)
+}
+
+func TestCat(t *testing.T) {
+	AssertReduce(t, Cat(), Slice{Slice{1, 2}, Slice{}, Slice{3}}, 1, 2, 3)
+	AssertReduce(t, Comp(PartitionAll(2), Cat(), Take(3)), Slice{1, 2, 3, 4}, 1, 2, 3)
+}
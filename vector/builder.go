@@ -0,0 +1,116 @@
+package vector
+
+import (
+	"../transducer"
+)
+
+// Transient vector that appends in place.
+// Nodes shared with the vector it was built from are copied on first write,
+// after which the builder owns them and mutates them directly.
+type Builder struct {
+	// The root node being built
+	root *Node
+	// The number of elements in the vector being built
+	length uint32
+	// The key at which the vector starts
+	offset uint32
+	// The nodes allocated by this builder, safe to mutate
+	owned map[*Node]bool
+}
+
+// Return a new builder appending to vec.
+// vec itself is never modified.
+// Complexity: O(1)
+func NewBuilder(vec *Vector) *Builder {
+	return &Builder{
+		root:   vec.Root,
+		length: vec.Length,
+		offset: vec.Offset,
+		owned:  make(map[*Node]bool),
+	}
+}
+
+// Return node if this builder owns it, or else an owned copy of it.
+// Complexity: O(1)
+func (b *Builder) own(node *Node) *Node {
+	if b.owned[node] {
+		return node
+	}
+
+	into := node.Copy()
+	b.owned[into] = true
+	return into
+}
+
+// Return the number of elements appended so far, including those of the
+// original vector.
+// Complexity: O(1)
+func (b *Builder) Count() uint32 {
+	return b.length
+}
+
+// Append a value to the end of the vector being built.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (b *Builder) Append(value Value) *Builder {
+	key := b.offset + b.length
+
+	if uint64(key) >= uint64(1)<<(b.root.Shift+BITS) {
+		b.root = NewNode(b.root.Shift+BITS, b.root)
+		b.owned[b.root] = true
+	}
+
+	b.root = b.own(b.root)
+	node := b.root
+
+	for node.Shift > 0 {
+		idx := (key >> node.Shift) & MASK
+		if node.Elements[idx] == Null {
+			child := NewNode(node.Shift - BITS)
+			b.owned[child] = true
+			node.Elements[idx] = child
+		} else {
+			node.Elements[idx] = b.own(node.Child(idx))
+		}
+		node = node.Elements[idx].(*Node)
+	}
+
+	node.Elements[key&MASK] = value
+	b.length++
+
+	return b
+}
+
+// Return the built vector.
+// The builder gives up ownership of its nodes, so it may keep appending
+// without affecting the returned vector.
+// Complexity: O(1)
+func (b *Builder) Persistent() *Vector {
+	b.owned = make(map[*Node]bool)
+
+	return &Vector{
+		Root:   b.root,
+		Length: b.length,
+		Offset: b.offset,
+	}
+}
+
+// Return vec with each value of source appended, transformed by xform.
+// The whole pipeline runs in a single pass, appending into a Builder.
+// A nil xform appends the values unchanged.
+// A new vector is returned, sharing memory with the original.
+// Complexity: O(n)
+func Into(vec *Vector, xform transducer.Transducer, source transducer.Source) *Vector {
+	b := NewBuilder(vec)
+
+	transducer.Reduce(
+		xform,
+		transducer.ReducerFunc(func(acc, v Value) (Value, bool) {
+			return acc.(*Builder).Append(v), true
+		}),
+		b,
+		source,
+	)
+
+	return b.Persistent()
+}
@@ -25,6 +25,14 @@ type Node struct {
 	Shift uint32
 }
 
+// A reference to a node stored elsewhere, such as on disk.
+// Branches may hold a Loader in place of a *Node, which is loaded when the
+// branch is first visited.
+type Loader interface {
+	// Return the referenced node
+	Load() *Node
+}
+
 // Create a new empty root node.
 // Complexity: O(1)
 func EmptyNode() *Node {
@@ -54,11 +62,18 @@ func NewNode(shift uint32, elements ...Value) *Node {
 // Complexity: O(log(n))
 // Effectively: O(1)
 func (node *Node) Get(key uint32) Value {
+	return node.Leaf(key).Elements[(key & MASK)]
+}
+
+// Find the leaf node holding a given key starting from this node.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (node *Node) Leaf(key uint32) *Node {
 	for node.Shift > 0 {
-		node = node.Elements[((key >> node.Shift) & MASK)].(*Node)
+		node = node.Child((key >> node.Shift) & MASK)
 	}
 
-	return node.Elements[(key & MASK)]
+	return node
 }
 
 // Set key in vector to value, returning a new root node.
@@ -108,8 +123,8 @@ func (node *Node) Truncate(length uint32) (into *Node) {
 	}
 
 	// Root node with only one child
-	for into.Shift > 0 && length < (1<<(into.Shift+BITS)) {
-		into = into.Elements[0].(*Node)
+	for into.Shift > 0 && length <= (1<<into.Shift) {
+		into = into.Child(0)
 	}
 
 	return
@@ -148,6 +163,15 @@ func (node *Node) EraseTo(length uint32) (into *Node) {
 	return
 }
 
+// Return the child node at idx in this branch, loading it if necessary.
+// Complexity: O(1), plus loading
+func (node *Node) Child(idx uint32) *Node {
+	if child, ok := node.Elements[idx].(*Node); ok {
+		return child
+	}
+	return node.Elements[idx].(Loader).Load()
+}
+
 // Make a shallow copy of this node.
 // This copies the node and its internal slice, but not its branches or values.
 // Complexity: O(1)
@@ -159,7 +183,7 @@ func (node *Node) Copy() *Node {
 // A new root has an increased shift size.
 // Complexity: O(1)
 func (node *Node) NewRoot(key uint32) *Node {
-	if (1 << node.Shift) < (key >> BITS) {
+	if uint64(key) < uint64(1)<<(node.Shift+BITS) {
 		return node.Copy()
 	} else {
 		return NewNode(node.Shift+BITS, node)
@@ -175,7 +199,7 @@ func (node *Node) CopySubKey(key uint32) (into *Node) {
 		into = NewNode(node.Shift - BITS)
 		node.Elements[key] = into
 	} else {
-		into = node.Elements[key].(*Node).Copy()
+		into = node.Child(key).Copy()
 		node.Elements[key] = into
 	}
 
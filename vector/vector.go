@@ -1,7 +1,7 @@
 package vector
 
 // Values storable in the vector
-type Value interface{}
+type Value = interface{}
 
 // Pointer to the root node and its length
 type Vector struct {
@@ -48,6 +48,15 @@ func (vec *Vector) Get(key uint32) (Value, error) {
 	return nil, &OutOfBounds{key}
 }
 
+// Get the value for a given key in the vector.
+// Returns false if key is not in the vector.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (vec *Vector) Lookup(key uint32) (Value, bool) {
+	v, err := vec.Get(key)
+	return v, err == nil
+}
+
 // Set a given key in the vector.
 // Allowed indices are those already set, and that in the append position.
 // Attempts to set key > length is an OutOfBounds error.
@@ -71,6 +80,20 @@ func (vec *Vector) Set(key uint32, value Value) (*Vector, error) {
 	}, nil
 }
 
+// Associate key with value, appending if key is the append position.
+// Attempts to associate key > length panic with an OutOfBounds error.
+// A new vector is returned, sharing memory with the original.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (vec *Vector) Assoc(key uint32, value Value) *Vector {
+	vec, err := vec.Set(key, value)
+	if err != nil {
+		panic(err)
+	}
+
+	return vec
+}
+
 // Append a value to the end of this vector.
 // A new vector is returned, sharing memory with the original.
 // Complexity: O(log(n))
@@ -105,6 +128,57 @@ func (vec *Vector) Prepend(value Value) *Vector {
 	}
 }
 
+// Insert value before key, shifting later elements up.
+// Allowed indices are those already set, and that in the append position.
+// Attempts to insert at key > length is an OutOfBounds error.
+// A new vector is returned, sharing memory with the original on the side of
+// key further from the nearest end.
+// Complexity: O(min(key, n - key))
+func (vec *Vector) Insert(key uint32, value Value) (*Vector, error) {
+	if key > vec.Length {
+		return nil, &OutOfBounds{key}
+	}
+
+	if key < vec.Length-key {
+		acc := vec.Drop(key).Prepend(value)
+		for i := key; i > 0; i-- {
+			acc = acc.Prepend(vec.Root.Get(vec.Offset + i - 1))
+		}
+		return acc, nil
+	}
+
+	acc := vec.Truncate(key).Append(value)
+	for i := key; i < vec.Length; i++ {
+		acc = acc.Append(vec.Root.Get(vec.Offset + i))
+	}
+	return acc, nil
+}
+
+// Remove the element at key, shifting later elements down.
+// Attempts to remove a key that is not in the vector is an OutOfBounds error.
+// A new vector is returned, sharing memory with the original on the side of
+// key further from the nearest end.
+// Complexity: O(min(key, n - key))
+func (vec *Vector) Remove(key uint32) (*Vector, error) {
+	if key >= vec.Length {
+		return nil, &OutOfBounds{key}
+	}
+
+	if key < vec.Length-key {
+		acc := vec.Drop(key + 1)
+		for i := key; i > 0; i-- {
+			acc = acc.Prepend(vec.Root.Get(vec.Offset + i - 1))
+		}
+		return acc, nil
+	}
+
+	acc := vec.Truncate(key)
+	for i := key + 1; i < vec.Length; i++ {
+		acc = acc.Append(vec.Root.Get(vec.Offset + i))
+	}
+	return acc, nil
+}
+
 // Return the vector with all elements > length removed.
 // A new vector is returned, sharing memory with the original.
 // Attempting to truncate to a length > the current length returns itself.
@@ -166,3 +240,39 @@ func (vec *Vector) Shift() *Vector {
 
 	return vec.Drop(1)
 }
+
+// Get the last element of this vector.
+// Reading from an empty vector is an OutOfBounds error.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (vec *Vector) Peek() (Value, error) {
+	if vec.Length == 0 {
+		return nil, &OutOfBounds{0}
+	}
+
+	return vec.Get(vec.Length - 1)
+}
+
+// Add a value to this vector in its natural position, at the end.
+// A new vector is returned, sharing memory with the original.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (vec *Vector) Conj(value Value) *Vector {
+	return vec.Append(value)
+}
+
+// Return the empty vector.
+// Complexity: O(1)
+func (vec *Vector) Empty() *Vector {
+	return empty
+}
+
+// Call fn with each element in order, until fn returns false.
+// Complexity: O(n)
+func (vec *Vector) Each(fn func(Value) bool) {
+	for i := uint32(0); i < vec.Length; i++ {
+		if !fn(vec.Root.Get(vec.Offset + i)) {
+			return
+		}
+	}
+}
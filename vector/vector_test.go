@@ -1,6 +1,7 @@
 package vector
 
 import (
+	"../transducer"
 	"testing"
 )
 
@@ -706,3 +707,211 @@ func TestNewWithArgs(t *testing.T) {
 		t.Fatalf(`expected vec.Count() == 0, got %s`, vec.Count())
 	}
 }
+
+func TestPeek(t *testing.T) {
+	vec := New(42, 7, 19)
+
+	x, err := vec.Peek()
+	if err != nil {
+		t.Fatalf(`expected vec.Peek() to be ok, got %s`, err)
+	}
+	if x != 19 {
+		t.Fatalf(`expected vec.Peek() == 19, got %v`, x)
+	}
+
+	if _, err := New().Peek(); err == nil {
+		t.Fatalf(`expected New().Peek() not to be ok, but was`)
+	}
+}
+
+func TestConjAndEmpty(t *testing.T) {
+	vec := New(42).Conj(7)
+
+	AssertContains(t, vec, map[uint32]Value{0: 42, 1: 7})
+
+	if vec.Empty().Count() != 0 {
+		t.Fatalf(`expected vec.Empty().Count() == 0, got %d`, vec.Empty().Count())
+	}
+}
+
+func TestLookupAndAssoc(t *testing.T) {
+	vec := New(42).Assoc(0, 7).Assoc(1, 19)
+
+	AssertContains(t, vec, map[uint32]Value{0: 7, 1: 19})
+
+	if x, ok := vec.Lookup(1); !ok || x != 19 {
+		t.Fatalf(`expected vec.Lookup(1) == 19, got %v, %t`, x, ok)
+	}
+	if _, ok := vec.Lookup(2); ok {
+		t.Fatalf(`expected vec.Lookup(2) not to be ok, but was`)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf(`expected vec.Assoc(3) to panic, but did not`)
+		}
+	}()
+	vec.Assoc(3, 1)
+}
+
+func TestInsert(t *testing.T) {
+	vec := New()
+	for i := 0; i < 100; i++ {
+		vec = vec.Append(i)
+	}
+
+	for _, key := range []uint32{0, 10, 90, 100} {
+		cpy, err := vec.Insert(key, "x")
+		if err != nil {
+			t.Fatalf(`expected vec.Insert(%d) to be ok, got %s`, key, err)
+		}
+		if cpy.Count() != 101 {
+			t.Fatalf(`expected cpy.Count() == 101, got %d`, cpy.Count())
+		}
+		AssertContains(t, cpy, map[uint32]Value{key: "x"})
+		for i := uint32(0); i < 100; i++ {
+			j := i
+			if i >= key {
+				j++
+			}
+			AssertContains(t, cpy, map[uint32]Value{j: int(i)})
+		}
+	}
+
+	AssertContains(t, vec, map[uint32]Value{0: 0, 99: 99})
+
+	if _, err := vec.Insert(101, "x"); err == nil {
+		t.Fatalf(`expected vec.Insert(101) not to be ok, but was`)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	vec := New()
+	for i := 0; i < 100; i++ {
+		vec = vec.Append(i)
+	}
+
+	for _, key := range []uint32{0, 10, 90, 99} {
+		cpy, err := vec.Remove(key)
+		if err != nil {
+			t.Fatalf(`expected vec.Remove(%d) to be ok, got %s`, key, err)
+		}
+		if cpy.Count() != 99 {
+			t.Fatalf(`expected cpy.Count() == 99, got %d`, cpy.Count())
+		}
+		for i := uint32(0); i < 99; i++ {
+			j := i
+			if i >= key {
+				j++
+			}
+			AssertContains(t, cpy, map[uint32]Value{i: int(j)})
+		}
+	}
+
+	AssertContains(t, vec, map[uint32]Value{0: 0, 99: 99})
+
+	if _, err := vec.Remove(100); err == nil {
+		t.Fatalf(`expected vec.Remove(100) not to be ok, but was`)
+	}
+}
+
+func TestEach(t *testing.T) {
+	vec := New(1, 2, 3, 4).Shift()
+
+	var found []Value
+	vec.Each(func(x Value) bool {
+		found = append(found, x)
+		return len(found) < 2
+	})
+
+	if len(found) != 2 || found[0] != 2 || found[1] != 3 {
+		t.Fatalf(`expected vec.Each() to visit 2 then 3, got %v`, found)
+	}
+}
+
+func TestAppendKeepsTreeShallow(t *testing.T) {
+	vec := New()
+	for i := 0; i < 5000; i++ {
+		vec = vec.Append(i)
+	}
+
+	if vec.Root.Shift != 2*BITS {
+		t.Fatalf(`expected vec.Root.Shift == %d, got %d`, 2*BITS, vec.Root.Shift)
+	}
+
+	cpy := vec.Truncate(SIZE)
+	if cpy.Root.Shift != 0 {
+		t.Fatalf(`expected cpy.Root.Shift == 0, got %d`, cpy.Root.Shift)
+	}
+
+	for i := uint32(0); i < 5000; i += 97 {
+		if x, _ := vec.Get(i); x != int(i) {
+			t.Fatalf(`expected vec.Get(%d) == %d, got %v`, i, i, x)
+		}
+	}
+}
+
+func TestTruncateAfterPrepend(t *testing.T) {
+	vec := New()
+	for i := 99; i >= 0; i-- {
+		vec = vec.Prepend(i)
+	}
+	for i := 100; i < 200; i++ {
+		vec = vec.Append(i)
+	}
+
+	cpy := vec.Truncate(3)
+	if cpy.Count() != 3 {
+		t.Fatalf(`expected cpy.Count() == 3, got %d`, cpy.Count())
+	}
+	for i := uint32(0); i < 3; i++ {
+		if x, _ := cpy.Get(i); x != int(i) {
+			t.Fatalf(`expected cpy.Get(%d) == %d, got %v`, i, i, x)
+		}
+	}
+	if x, _ := vec.Get(150); x != 150 {
+		t.Fatalf(`expected vec.Get(150) == 150, got %v`, x)
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	vec := New(1, 2, 3)
+	b := NewBuilder(vec)
+	for i := 4; i <= 100; i++ {
+		b.Append(i)
+	}
+
+	cpy := b.Persistent()
+	b.Append(101)
+
+	if vec.Count() != 3 || cpy.Count() != 100 {
+		t.Fatalf(`expected counts 3 and 100, got %d and %d`, vec.Count(), cpy.Count())
+	}
+	if b.Persistent().Count() != 101 {
+		t.Fatalf(`expected the builder to keep appending after Persistent()`)
+	}
+	for i := uint32(0); i < 100; i++ {
+		if x, _ := cpy.Get(i); x != int(i+1) {
+			t.Fatalf(`expected cpy.Get(%d) == %d, got %v`, i, i+1, x)
+		}
+	}
+	if _, err := cpy.Get(100); err == nil {
+		t.Fatalf(`expected cpy.Get(100) not to be ok, but was`)
+	}
+}
+
+func TestInto(t *testing.T) {
+	xform := transducer.Comp(
+		transducer.Filter(func(v Value) bool { return v.(int)%2 == 0 }),
+		transducer.Map(func(v Value) Value { return v.(int) * 10 }),
+	)
+
+	vec := New(-1).Prepend(-2)
+	cpy := Into(vec, xform, New(1, 2, 3, 4))
+
+	AssertContains(t, cpy, map[uint32]Value{0: -2, 1: -1, 2: 20, 3: 40})
+
+	if cpy.Count() != 4 || vec.Count() != 2 {
+		t.Fatalf(`expected counts 4 and 2, got %d and %d`, cpy.Count(), vec.Count())
+	}
+}
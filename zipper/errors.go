@@ -0,0 +1,15 @@
+package zipper
+
+import (
+	"fmt"
+)
+
+// Error type returned when a move or edit is impossible from a location
+type InvalidMove struct {
+	// The name of the attempted move
+	Move string
+}
+
+func (e *InvalidMove) Error() string {
+	return fmt.Sprintf("cannot move %s from this location", e.Move)
+}
@@ -0,0 +1,331 @@
+package zipper
+
+import (
+	"../vector"
+)
+
+// Values storable in the tree
+type Value = interface{}
+
+// Location in a tree of nested vectors.
+// Every *vector.Vector is a branch and every other value is a leaf. Moving up
+// sets the location back into its parent only if something beneath it
+// changed, so Root copies just the edited paths.
+type Zipper struct {
+	// The value at this location
+	Node Value
+	// The path from the root to this location, nil at the root
+	Path *Path
+}
+
+// The context of a location within its parent
+type Path struct {
+	// The parent, with every edit applied except the value at Index
+	Node *vector.Vector
+	// The key of the location within Node
+	Index uint32
+	// The path to the parent
+	Parent *Path
+	// True if Node differs from the parent as it was before moving down
+	Changed bool
+	// True if the value at the location differs from the one in Node
+	Edited bool
+}
+
+// Return a new zipper at the root of tree.
+// Complexity: O(1)
+func New(tree Value) *Zipper {
+	return &Zipper{Node: tree}
+}
+
+// Return true if this location is a branch that can have children.
+// Complexity: O(1)
+func (z *Zipper) IsBranch() bool {
+	_, ok := z.Node.(*vector.Vector)
+	return ok
+}
+
+// Return true if this location is the root of the tree.
+// Complexity: O(1)
+func (z *Zipper) IsRoot() bool {
+	return z.Path == nil
+}
+
+// Return a copy of p marked as edited.
+// Complexity: O(1)
+func edited(p *Path) *Path {
+	if p == nil || p.Edited {
+		return p
+	}
+
+	return &Path{
+		Node:    p.Node,
+		Index:   p.Index,
+		Parent:  p.Parent,
+		Changed: p.Changed,
+		Edited:  true,
+	}
+}
+
+// Return the parent of the location at p, with value set at p.Index if it
+// was edited.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (p *Path) settle(value Value) *vector.Vector {
+	if !p.Edited {
+		return p.Node
+	}
+
+	node, _ := p.Node.Set(p.Index, value)
+	return node
+}
+
+// Move to the first child of this location.
+// Moving down from a leaf or an empty branch is an InvalidMove error.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (z *Zipper) Down() (*Zipper, error) {
+	node, ok := z.Node.(*vector.Vector)
+	if !ok || node.Count() == 0 {
+		return nil, &InvalidMove{"down"}
+	}
+
+	first, _ := node.Get(0)
+	return &Zipper{
+		Node: first,
+		Path: &Path{
+			Node:   node,
+			Parent: z.Path,
+		},
+	}, nil
+}
+
+// Move to the parent of this location, setting this location back into it
+// if it changed.
+// Moving up from the root is an InvalidMove error.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (z *Zipper) Up() (*Zipper, error) {
+	p := z.Path
+	if p == nil {
+		return nil, &InvalidMove{"up"}
+	}
+
+	if !p.Changed && !p.Edited {
+		return &Zipper{Node: p.Node, Path: p.Parent}, nil
+	}
+
+	return &Zipper{Node: p.settle(z.Node), Path: edited(p.Parent)}, nil
+}
+
+// Move to the sibling left of this location.
+// Moving left from the leftmost sibling is an InvalidMove error.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (z *Zipper) Left() (*Zipper, error) {
+	p := z.Path
+	if p == nil || p.Index == 0 {
+		return nil, &InvalidMove{"left"}
+	}
+
+	prev, _ := p.Node.Get(p.Index - 1)
+	return &Zipper{
+		Node: prev,
+		Path: &Path{
+			Node:    p.settle(z.Node),
+			Index:   p.Index - 1,
+			Parent:  p.Parent,
+			Changed: p.Changed || p.Edited,
+		},
+	}, nil
+}
+
+// Move to the sibling right of this location.
+// Moving right from the rightmost sibling is an InvalidMove error.
+// Complexity: O(log(n))
+// Effectively: O(1)
+func (z *Zipper) Right() (*Zipper, error) {
+	p := z.Path
+	if p == nil || p.Index+1 >= p.Node.Count() {
+		return nil, &InvalidMove{"right"}
+	}
+
+	next, _ := p.Node.Get(p.Index + 1)
+	return &Zipper{
+		Node: next,
+		Path: &Path{
+			Node:    p.settle(z.Node),
+			Index:   p.Index + 1,
+			Parent:  p.Parent,
+			Changed: p.Changed || p.Edited,
+		},
+	}, nil
+}
+
+// Move to the rightmost sibling of this location, or stay if already there.
+// Complexity: O(n) for n siblings
+func (z *Zipper) Rightmost() *Zipper {
+	for {
+		next, err := z.Right()
+		if err != nil {
+			return z
+		}
+		z = next
+	}
+}
+
+// Move to the leftmost sibling of this location, or stay if already there.
+// Complexity: O(n) for n siblings
+func (z *Zipper) Leftmost() *Zipper {
+	for {
+		prev, err := z.Left()
+		if err != nil {
+			return z
+		}
+		z = prev
+	}
+}
+
+// Return the root of the tree, with every edit applied.
+// Complexity: O(d log(n)) for depth d
+// Effectively: O(d)
+func (z *Zipper) Root() Value {
+	for {
+		up, err := z.Up()
+		if err != nil {
+			return z.Node
+		}
+		z = up
+	}
+}
+
+// Move to the next location in depth-first pre-order.
+// Moving past the last location is an InvalidMove error.
+// Complexity: O(d) worst case, for depth d
+func (z *Zipper) Next() (*Zipper, error) {
+	if child, err := z.Down(); err == nil {
+		return child, nil
+	}
+
+	for loc := z; loc.Path != nil; loc, _ = loc.Up() {
+		if next, err := loc.Right(); err == nil {
+			return next, nil
+		}
+	}
+
+	return nil, &InvalidMove{"next"}
+}
+
+// Move to the previous location in depth-first pre-order.
+// Moving before the root is an InvalidMove error.
+// Complexity: O(d) worst case, for depth d
+func (z *Zipper) Prev() (*Zipper, error) {
+	loc, err := z.Left()
+	if err != nil {
+		return z.Up()
+	}
+
+	return loc.lastDescendant(), nil
+}
+
+// Move to the last location in depth-first pre-order beneath this one.
+// Complexity: O(d) for depth d
+func (z *Zipper) lastDescendant() *Zipper {
+	for {
+		child, err := z.Down()
+		if err != nil {
+			return z
+		}
+		z = child.Rightmost()
+	}
+}
+
+// Replace the value at this location.
+// A new zipper is returned, sharing memory with the original.
+// Complexity: O(1)
+func (z *Zipper) Replace(value Value) *Zipper {
+	return &Zipper{Node: value, Path: edited(z.Path)}
+}
+
+// Replace the value at this location with the result of fn.
+// A new zipper is returned, sharing memory with the original.
+// Complexity: O(1)
+func (z *Zipper) Edit(fn func(Value) Value) *Zipper {
+	return z.Replace(fn(z.Node))
+}
+
+// Insert a sibling to the left of this location, without moving.
+// Inserting beside the root is an InvalidMove error.
+// A new zipper is returned, sharing memory with the original.
+// Complexity: O(min(i, n - i)) for the i-th of n siblings
+func (z *Zipper) InsertLeft(value Value) (*Zipper, error) {
+	p := z.Path
+	if p == nil {
+		return nil, &InvalidMove{"insert left"}
+	}
+
+	node, _ := p.Node.Insert(p.Index, value)
+	return &Zipper{
+		Node: z.Node,
+		Path: &Path{
+			Node:    node,
+			Index:   p.Index + 1,
+			Parent:  p.Parent,
+			Changed: true,
+			Edited:  p.Edited,
+		},
+	}, nil
+}
+
+// Insert a sibling to the right of this location, without moving.
+// Inserting beside the root is an InvalidMove error.
+// A new zipper is returned, sharing memory with the original.
+// Complexity: O(min(i, n - i)) for the i-th of n siblings
+func (z *Zipper) InsertRight(value Value) (*Zipper, error) {
+	p := z.Path
+	if p == nil {
+		return nil, &InvalidMove{"insert right"}
+	}
+
+	node, _ := p.Node.Insert(p.Index+1, value)
+	return &Zipper{
+		Node: z.Node,
+		Path: &Path{
+			Node:    node,
+			Index:   p.Index,
+			Parent:  p.Parent,
+			Changed: true,
+			Edited:  p.Edited,
+		},
+	}, nil
+}
+
+// Remove this location, moving to the previous location in depth-first
+// pre-order.
+// Removing the root is an InvalidMove error.
+// A new zipper is returned, sharing memory with the original.
+// Complexity: O(min(i, n - i)) for the i-th of n siblings, plus O(d) for
+// depth d
+func (z *Zipper) Remove() (*Zipper, error) {
+	p := z.Path
+	if p == nil {
+		return nil, &InvalidMove{"remove"}
+	}
+
+	node, _ := p.Node.Remove(p.Index)
+	if p.Index == 0 {
+		return &Zipper{Node: node, Path: edited(p.Parent)}, nil
+	}
+
+	prev, _ := node.Get(p.Index - 1)
+	loc := &Zipper{
+		Node: prev,
+		Path: &Path{
+			Node:    node,
+			Index:   p.Index - 1,
+			Parent:  p.Parent,
+			Changed: true,
+		},
+	}
+	return loc.lastDescendant(), nil
+}
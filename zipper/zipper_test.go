@@ -0,0 +1,185 @@
+package zipper
+
+import (
+	"../vector"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Render a tree of nested vectors as [a [b c]]
+func Show(v Value) string {
+	vec, ok := v.(*vector.Vector)
+	if !ok {
+		return fmt.Sprint(v)
+	}
+
+	var parts []string
+	vec.Each(func(x vector.Value) bool {
+		parts = append(parts, Show(x))
+		return true
+	})
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+func AssertTree(t *testing.T, v Value, expected string) {
+	if s := Show(v); s != expected {
+		t.Fatalf(`expected %s, got %s`, expected, s)
+	}
+}
+
+// The tree [1 [2 3] [4 [5]] 6]
+func Tree() *vector.Vector {
+	return vector.New(1, vector.New(2, 3), vector.New(4, vector.New(5)), 6)
+}
+
+func Must(z *Zipper, err error) *Zipper {
+	if err != nil {
+		panic(err)
+	}
+	return z
+}
+
+func TestNavigation(t *testing.T) {
+	z := New(Tree())
+
+	child := Must(Must(z.Down()).Right())
+	AssertTree(t, child.Node, "[2 3]")
+
+	leaf := Must(child.Down()).Rightmost()
+	if leaf.Node != 3 {
+		t.Fatalf(`expected leaf.Node == 3, got %v`, leaf.Node)
+	}
+	if _, err := leaf.Down(); err == nil {
+		t.Fatalf(`expected leaf.Down() not to be ok, but was`)
+	}
+	if _, err := leaf.Right(); err == nil {
+		t.Fatalf(`expected leaf.Right() not to be ok, but was`)
+	}
+	if Must(leaf.Left()).Node != 2 {
+		t.Fatalf(`expected the left of 3 to be 2`)
+	}
+	if _, err := z.Up(); err == nil {
+		t.Fatalf(`expected z.Up() not to be ok, but was`)
+	}
+
+	if leaf.Root() != z.Node {
+		t.Fatalf(`expected navigating without edits to return the original root`)
+	}
+}
+
+func TestNextAndPrev(t *testing.T) {
+	var visited []string
+	z := New(Tree())
+	for {
+		visited = append(visited, Show(z.Node))
+		next, err := z.Next()
+		if err != nil {
+			break
+		}
+		z = next
+	}
+
+	expected := "[1 [2 3] [4 [5]] 6] 1 [2 3] 2 3 [4 [5]] 4 [5] 5 6"
+	if strings.Join(visited, " ") != expected {
+		t.Fatalf(`expected pre-order %s, got %s`, expected, strings.Join(visited, " "))
+	}
+
+	for i := len(visited) - 1; i > 0; i-- {
+		z = Must(z.Prev())
+		if Show(z.Node) != visited[i-1] {
+			t.Fatalf(`expected Prev() to visit %s, got %s`, visited[i-1], Show(z.Node))
+		}
+	}
+	if _, err := z.Prev(); err == nil {
+		t.Fatalf(`expected z.Prev() at the root not to be ok, but was`)
+	}
+}
+
+func TestEditSharesUntouchedPaths(t *testing.T) {
+	tree := Tree()
+	z := Must(Must(Must(New(tree).Down()).Right()).Down())
+
+	root := z.Edit(func(v Value) Value {
+		return v.(int) * 10
+	}).Root()
+
+	AssertTree(t, root, "[1 [20 3] [4 [5]] 6]")
+	AssertTree(t, tree, "[1 [2 3] [4 [5]] 6]")
+
+	before, _ := tree.Get(2)
+	after, _ := root.(*vector.Vector).Get(2)
+	if before != after {
+		t.Fatalf(`expected the untouched branch to be shared`)
+	}
+}
+
+func TestInsert(t *testing.T) {
+	z := Must(Must(New(Tree()).Down()).Right())
+	z = Must(z.InsertLeft("a"))
+	z = Must(z.InsertRight("b"))
+
+	AssertTree(t, z.Root(), "[1 a [2 3] b [4 [5]] 6]")
+
+	if _, err := New(Tree()).InsertLeft("x"); err == nil {
+		t.Fatalf(`expected inserting beside the root not to be ok, but was`)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	z := Must(Must(New(Tree()).Down()).Right())
+	z = Must(z.Right()) // [4 [5]]
+
+	prev := Must(z.Remove())
+	if prev.Node != 3 {
+		t.Fatalf(`expected to move to the previous location 3, got %v`, prev.Node)
+	}
+	AssertTree(t, prev.Root(), "[1 [2 3] 6]")
+
+	first := Must(Must(Must(New(Tree()).Down()).Right()).Down())
+	parent := Must(first.Remove())
+	AssertTree(t, parent.Node, "[3]")
+	AssertTree(t, parent.Root(), "[1 [3] [4 [5]] 6]")
+
+	if _, err := New(Tree()).Remove(); err == nil {
+		t.Fatalf(`expected removing the root not to be ok, but was`)
+	}
+}
+
+func TestEditSharesUntouchedSiblings(t *testing.T) {
+	var elements []vector.Value
+	for i := 0; i < 10000; i++ {
+		elements = append(elements, i)
+	}
+	tree := vector.New(elements...)
+
+	z := Must(New(tree).Down())
+	for i := 0; i < 5000; i++ {
+		z = Must(z.Right())
+	}
+	z = z.Replace(-1)
+	z = Must(z.Right()).Replace(-2)
+
+	root := z.Root().(*vector.Vector)
+	if v, _ := root.Get(5000); v != -1 {
+		t.Fatalf(`expected root.Get(5000) == -1, got %v`, v)
+	}
+	if v, _ := root.Get(5001); v != -2 {
+		t.Fatalf(`expected root.Get(5001) == -2, got %v`, v)
+	}
+	if v, _ := tree.Get(5000); v != 5000 {
+		t.Fatalf(`expected the original to be unchanged, got %v`, v)
+	}
+	if root.Root.Leaf(0) != tree.Root.Leaf(0) || root.Root.Leaf(9999) != tree.Root.Leaf(9999) {
+		t.Fatalf(`expected untouched leaves to be shared`)
+	}
+
+	z = Must(z.InsertLeft("a"))
+	root = Must(z.Remove()).Root().(*vector.Vector)
+	if root.Count() != 10000 {
+		t.Fatalf(`expected root.Count() == 10000, got %d`, root.Count())
+	}
+	if v, _ := root.Get(5001); v != "a" {
+		t.Fatalf(`expected root.Get(5001) == "a", got %v`, v)
+	}
+}